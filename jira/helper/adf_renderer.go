@@ -0,0 +1,421 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ADFFormat selects the output flavour of the Atlassian Document Format renderer
+type ADFFormat int
+
+const (
+	// ADFPlainText renders readable text with line breaks but no markup
+	ADFPlainText ADFFormat = iota
+	// ADFMarkdown renders GitHub-flavoured Markdown
+	ADFMarkdown
+)
+
+// panelLabels maps ADF panel types to the label shown in front of the panel content
+var panelLabels = map[string]string{
+	"info":    "Info",
+	"note":    "Note",
+	"warning": "Warning",
+	"error":   "Error",
+	"success": "Success",
+	"tip":     "Tip",
+}
+
+// adfRenderer walks an ADF document tree and renders it in the selected format
+type adfRenderer struct {
+	format ADFFormat
+}
+
+// renderADF renders an ADF node (usually the "doc" root) as plain text or Markdown
+func renderADF(node interface{}, format ADFFormat) string {
+	r := &adfRenderer{format: format}
+	return strings.TrimSpace(r.renderBlock(node))
+}
+
+// renderBlocks renders a list of block nodes separated by blank lines
+func (r *adfRenderer) renderBlocks(content []interface{}) string {
+	var blocks []string
+	for _, child := range content {
+		if text := r.renderBlock(child); strings.TrimSpace(text) != "" {
+			blocks = append(blocks, text)
+		}
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// renderBlock renders a single block-level node
+func (r *adfRenderer) renderBlock(node interface{}) string {
+	nodeMap, ok := node.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	content := adfContent(nodeMap)
+
+	switch adfType(nodeMap) {
+	case "doc", "mediaSingle", "mediaGroup", "layoutSection", "layoutColumn", "bodiedExtension":
+		return r.renderBlocks(content)
+
+	case "paragraph":
+		return r.renderInlines(content)
+
+	case "heading":
+		text := r.renderInlines(content)
+		if r.format == ADFMarkdown {
+			level := adfIntAttr(nodeMap, "level", 1)
+			if level < 1 || level > 6 {
+				level = 1
+			}
+			return strings.Repeat("#", level) + " " + text
+		}
+		return text
+
+	case "bulletList":
+		return r.renderList(content, func(int) string { return "- " })
+
+	case "orderedList":
+		start := adfIntAttr(nodeMap, "order", 1)
+		return r.renderList(content, func(i int) string { return fmt.Sprintf("%d. ", start+i) })
+
+	case "taskList":
+		return r.renderList(content, func(int) string { return "" })
+
+	case "decisionList":
+		return r.renderList(content, func(int) string { return "- " })
+
+	case "listItem", "decisionItem":
+		return r.renderListItemBody(content)
+
+	case "taskItem":
+		box := "[ ] "
+		if adfStringAttr(nodeMap, "state") == "DONE" {
+			box = "[x] "
+		}
+		return "- " + box + r.renderInlines(content)
+
+	case "codeBlock":
+		code := r.renderPlainInlines(content)
+		if r.format == ADFMarkdown {
+			return "```" + adfStringAttr(nodeMap, "language") + "\n" + code + "\n```"
+		}
+		return code
+
+	case "blockquote":
+		inner := r.renderBlocks(content)
+		if r.format == ADFMarkdown {
+			return prefixLines(inner, "> ")
+		}
+		return inner
+
+	case "panel":
+		inner := r.renderBlocks(content)
+		label := panelLabels[adfStringAttr(nodeMap, "panelType")]
+		if label == "" {
+			label = "Note"
+		}
+		if r.format == ADFMarkdown {
+			return prefixLines("**"+label+":** "+inner, "> ")
+		}
+		return label + ": " + inner
+
+	case "expand", "nestedExpand":
+		inner := r.renderBlocks(content)
+		title := adfStringAttr(nodeMap, "title")
+		if title == "" {
+			return inner
+		}
+		if r.format == ADFMarkdown {
+			title = "**" + title + "**"
+		}
+		return title + "\n\n" + inner
+
+	case "rule":
+		if r.format == ADFMarkdown {
+			return "---"
+		}
+		return strings.Repeat("-", 10)
+
+	case "table":
+		return r.renderTable(content)
+
+	case "media":
+		return r.renderMedia(nodeMap)
+
+	default:
+		// Inline nodes or unknown block types: render whatever content they carry
+		if len(content) > 0 {
+			return r.renderBlocks(content)
+		}
+		return r.renderInline(nodeMap)
+	}
+}
+
+// renderList renders list items, prefixing each with the marker returned for its index
+func (r *adfRenderer) renderList(items []interface{}, marker func(int) string) string {
+	var lines []string
+	for i, item := range items {
+		body := r.renderBlock(item)
+		if body == "" {
+			continue
+		}
+		prefix := marker(i)
+		indent := strings.Repeat(" ", len(prefix))
+		bodyLines := strings.Split(body, "\n")
+		for j, line := range bodyLines {
+			switch {
+			case j == 0:
+				bodyLines[j] = prefix + line
+			case line != "":
+				bodyLines[j] = indent + line
+			}
+		}
+		lines = append(lines, strings.Join(bodyLines, "\n"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderListItemBody renders the blocks of a list item on consecutive lines so nested lists stay compact
+func (r *adfRenderer) renderListItemBody(content []interface{}) string {
+	var blocks []string
+	for _, child := range content {
+		if text := r.renderBlock(child); text != "" {
+			blocks = append(blocks, text)
+		}
+	}
+	return strings.Join(blocks, "\n")
+}
+
+// renderTable renders an ADF table; Markdown output treats the first row as the header row
+func (r *adfRenderer) renderTable(rows []interface{}) string {
+	var table [][]string
+	width := 0
+	for _, row := range rows {
+		rowMap, ok := row.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var cells []string
+		for _, cell := range adfContent(rowMap) {
+			cellMap, ok := cell.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			text := r.renderListItemBody(adfContent(cellMap))
+			if r.format == ADFMarkdown {
+				text = strings.ReplaceAll(text, "|", "\\|")
+				text = strings.ReplaceAll(text, "\n", "<br>")
+			} else {
+				text = strings.ReplaceAll(text, "\n", " ")
+			}
+			cells = append(cells, text)
+		}
+		if len(cells) > width {
+			width = len(cells)
+		}
+		table = append(table, cells)
+	}
+
+	if len(table) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	for i, cells := range table {
+		for len(cells) < width {
+			cells = append(cells, "")
+		}
+		if r.format == ADFMarkdown {
+			sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+			if i == 0 {
+				sb.WriteString("|" + strings.Repeat(" --- |", width) + "\n")
+			}
+		} else {
+			sb.WriteString(strings.Join(cells, " | ") + "\n")
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// renderMedia renders an attachment placeholder, since binary content cannot be shown inline
+func (r *adfRenderer) renderMedia(nodeMap map[string]interface{}) string {
+	if alt := adfStringAttr(nodeMap, "alt"); alt != "" {
+		return fmt.Sprintf("[attachment: %s]", alt)
+	}
+	return "[attachment]"
+}
+
+// renderInlines renders inline content (text, mentions, emoji, ...) in the selected format
+func (r *adfRenderer) renderInlines(content []interface{}) string {
+	var sb strings.Builder
+	for _, child := range content {
+		if childMap, ok := child.(map[string]interface{}); ok {
+			sb.WriteString(r.renderInline(childMap))
+		}
+	}
+	return sb.String()
+}
+
+// renderPlainInlines renders inline content without marks, as needed inside code blocks
+func (r *adfRenderer) renderPlainInlines(content []interface{}) string {
+	plain := &adfRenderer{format: ADFPlainText}
+	return plain.renderInlines(content)
+}
+
+// renderInline renders a single inline node
+func (r *adfRenderer) renderInline(nodeMap map[string]interface{}) string {
+	switch adfType(nodeMap) {
+	case "text":
+		text, _ := nodeMap["text"].(string)
+		return r.applyMarks(text, nodeMap)
+
+	case "hardBreak":
+		if r.format == ADFMarkdown {
+			return "  \n"
+		}
+		return "\n"
+
+	case "mention":
+		text := adfStringAttr(nodeMap, "text")
+		if text == "" {
+			text = adfStringAttr(nodeMap, "id")
+		}
+		if !strings.HasPrefix(text, "@") {
+			text = "@" + text
+		}
+		return text
+
+	case "emoji":
+		if text := adfStringAttr(nodeMap, "text"); text != "" {
+			return text
+		}
+		return adfStringAttr(nodeMap, "shortName")
+
+	case "inlineCard", "blockCard", "embedCard":
+		url := adfStringAttr(nodeMap, "url")
+		if r.format == ADFMarkdown && url != "" {
+			return fmt.Sprintf("<%s>", url)
+		}
+		return url
+
+	case "date":
+		ms, err := strconv.ParseInt(adfStringAttr(nodeMap, "timestamp"), 10, 64)
+		if err != nil {
+			return adfStringAttr(nodeMap, "timestamp")
+		}
+		return time.UnixMilli(ms).UTC().Format("2006-01-02")
+
+	case "status":
+		return "[" + adfStringAttr(nodeMap, "text") + "]"
+
+	case "placeholder":
+		return ""
+
+	default:
+		if content := adfContent(nodeMap); len(content) > 0 {
+			return r.renderInlines(content)
+		}
+		text, _ := nodeMap["text"].(string)
+		return text
+	}
+}
+
+// applyMarks applies ADF text marks (bold, italic, code, strike, link) to a text run
+func (r *adfRenderer) applyMarks(text string, nodeMap map[string]interface{}) string {
+	marks, _ := nodeMap["marks"].([]interface{})
+	if len(marks) == 0 || text == "" {
+		return text
+	}
+
+	href := ""
+	for _, mark := range marks {
+		markMap, ok := mark.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		markType := adfType(markMap)
+		if markType == "link" {
+			href = adfStringAttr(markMap, "href")
+			continue
+		}
+		if r.format != ADFMarkdown {
+			continue
+		}
+		switch markType {
+		case "strong":
+			text = "**" + text + "**"
+		case "em":
+			text = "*" + text + "*"
+		case "code":
+			text = "`" + text + "`"
+		case "strike":
+			text = "~~" + text + "~~"
+		}
+	}
+
+	if href == "" {
+		return text
+	}
+	if r.format == ADFMarkdown {
+		return fmt.Sprintf("[%s](%s)", text, href)
+	}
+	if text == href {
+		return text
+	}
+	return fmt.Sprintf("%s (%s)", text, href)
+}
+
+// prefixLines prefixes every line of text, used for Markdown blockquotes
+func prefixLines(text, prefix string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = strings.TrimRight(prefix, " ")
+			continue
+		}
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ADF node accessors - all handle missing or mistyped values safely
+
+func adfType(nodeMap map[string]interface{}) string {
+	nodeType, _ := nodeMap["type"].(string)
+	return nodeType
+}
+
+func adfContent(nodeMap map[string]interface{}) []interface{} {
+	content, _ := nodeMap["content"].([]interface{})
+	return content
+}
+
+func adfStringAttr(nodeMap map[string]interface{}, name string) string {
+	attrs, ok := nodeMap["attrs"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	switch v := attrs[name].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func adfIntAttr(nodeMap map[string]interface{}, name string, fallback int) int {
+	value, err := strconv.Atoi(adfStringAttr(nodeMap, name))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Helper functions to build ADF nodes
+func adfDoc(content ...interface{}) map[string]interface{} {
+	return map[string]interface{}{"type": "doc", "version": float64(1), "content": content}
+}
+
+func adfNode(nodeType string, attrs map[string]interface{}, content ...interface{}) map[string]interface{} {
+	node := map[string]interface{}{"type": nodeType}
+	if attrs != nil {
+		node["attrs"] = attrs
+	}
+	if len(content) > 0 {
+		node["content"] = content
+	}
+	return node
+}
+
+func adfText(text string, marks ...map[string]interface{}) map[string]interface{} {
+	node := map[string]interface{}{"type": "text", "text": text}
+	if len(marks) > 0 {
+		markList := make([]interface{}, 0, len(marks))
+		for _, mark := range marks {
+			markList = append(markList, mark)
+		}
+		node["marks"] = markList
+	}
+	return node
+}
+
+func adfMark(markType string, attrs map[string]interface{}) map[string]interface{} {
+	mark := map[string]interface{}{"type": markType}
+	if attrs != nil {
+		mark["attrs"] = attrs
+	}
+	return mark
+}
+
+func adfParagraph(content ...interface{}) map[string]interface{} {
+	return adfNode("paragraph", nil, content...)
+}
+
+func TestRenderADF(t *testing.T) {
+	tests := []struct {
+		name             string
+		input            interface{}
+		expectedPlain    string
+		expectedMarkdown string
+	}{
+		{
+			name:             "nil input",
+			input:            nil,
+			expectedPlain:    "",
+			expectedMarkdown: "",
+		},
+		{
+			name:             "paragraphs separated by blank line",
+			input:            adfDoc(adfParagraph(adfText("First")), adfParagraph(adfText("Second"))),
+			expectedPlain:    "First\n\nSecond",
+			expectedMarkdown: "First\n\nSecond",
+		},
+		{
+			name:             "heading",
+			input:            adfDoc(adfNode("heading", map[string]interface{}{"level": float64(2)}, adfText("Title"))),
+			expectedPlain:    "Title",
+			expectedMarkdown: "## Title",
+		},
+		{
+			name: "text marks and link",
+			input: adfDoc(adfParagraph(
+				adfText("bold", adfMark("strong", nil)),
+				adfText(" "),
+				adfText("italic", adfMark("em", nil)),
+				adfText(" "),
+				adfText("code", adfMark("code", nil)),
+				adfText(" "),
+				adfText("site", adfMark("link", map[string]interface{}{"href": "https://example.com"})),
+			)),
+			expectedPlain:    "bold italic code site (https://example.com)",
+			expectedMarkdown: "**bold** *italic* `code` [site](https://example.com)",
+		},
+		{
+			name: "hard break",
+			input: adfDoc(adfParagraph(
+				adfText("line one"),
+				adfNode("hardBreak", nil),
+				adfText("line two"),
+			)),
+			expectedPlain:    "line one\nline two",
+			expectedMarkdown: "line one  \nline two",
+		},
+		{
+			name: "bullet list with nested ordered list",
+			input: adfDoc(adfNode("bulletList", nil,
+				adfNode("listItem", nil,
+					adfParagraph(adfText("Parent")),
+					adfNode("orderedList", map[string]interface{}{"order": float64(1)},
+						adfNode("listItem", nil, adfParagraph(adfText("Child one"))),
+						adfNode("listItem", nil, adfParagraph(adfText("Child two"))),
+					),
+				),
+				adfNode("listItem", nil, adfParagraph(adfText("Sibling"))),
+			)),
+			expectedPlain:    "- Parent\n  1. Child one\n  2. Child two\n- Sibling",
+			expectedMarkdown: "- Parent\n  1. Child one\n  2. Child two\n- Sibling",
+		},
+		{
+			name: "code block",
+			input: adfDoc(adfNode("codeBlock", map[string]interface{}{"language": "go"},
+				adfText("fmt.Println(\"hi\")"),
+			)),
+			expectedPlain:    "fmt.Println(\"hi\")",
+			expectedMarkdown: "```go\nfmt.Println(\"hi\")\n```",
+		},
+		{
+			name: "table",
+			input: adfDoc(adfNode("table", nil,
+				adfNode("tableRow", nil,
+					adfNode("tableHeader", nil, adfParagraph(adfText("Name"))),
+					adfNode("tableHeader", nil, adfParagraph(adfText("Value"))),
+				),
+				adfNode("tableRow", nil,
+					adfNode("tableCell", nil, adfParagraph(adfText("a|b"))),
+					adfNode("tableCell", nil, adfParagraph(adfText("1"))),
+				),
+			)),
+			expectedPlain:    "Name | Value\na|b | 1",
+			expectedMarkdown: "| Name | Value |\n| --- | --- |\n| a\\|b | 1 |",
+		},
+		{
+			name: "mention, emoji, status and date",
+			input: adfDoc(adfParagraph(
+				adfNode("mention", map[string]interface{}{"id": "123", "text": "@Jane Doe"}),
+				adfText(" "),
+				adfNode("emoji", map[string]interface{}{"shortName": ":smile:", "text": "😄"}),
+				adfText(" "),
+				adfNode("emoji", map[string]interface{}{"shortName": ":custom:"}),
+				adfText(" "),
+				adfNode("status", map[string]interface{}{"text": "IN REVIEW"}),
+				adfText(" "),
+				adfNode("date", map[string]interface{}{"timestamp": "1735689600000"}),
+			)),
+			expectedPlain:    "@Jane Doe 😄 :custom: [IN REVIEW] 2025-01-01",
+			expectedMarkdown: "@Jane Doe 😄 :custom: [IN REVIEW] 2025-01-01",
+		},
+		{
+			name: "panel",
+			input: adfDoc(adfNode("panel", map[string]interface{}{"panelType": "warning"},
+				adfParagraph(adfText("Careful")),
+			)),
+			expectedPlain:    "Warning: Careful",
+			expectedMarkdown: "> **Warning:** Careful",
+		},
+		{
+			name: "blockquote and rule",
+			input: adfDoc(
+				adfNode("blockquote", nil, adfParagraph(adfText("Quoted"))),
+				adfNode("rule", nil),
+			),
+			expectedPlain:    "Quoted\n\n----------",
+			expectedMarkdown: "> Quoted\n\n---",
+		},
+		{
+			name: "task list",
+			input: adfDoc(adfNode("taskList", nil,
+				adfNode("taskItem", map[string]interface{}{"state": "DONE"}, adfText("Write code")),
+				adfNode("taskItem", map[string]interface{}{"state": "TODO"}, adfText("Ship it")),
+			)),
+			expectedPlain:    "- [x] Write code\n- [ ] Ship it",
+			expectedMarkdown: "- [x] Write code\n- [ ] Ship it",
+		},
+		{
+			name: "inline card and media",
+			input: adfDoc(
+				adfParagraph(adfNode("inlineCard", map[string]interface{}{"url": "https://example.com/x"})),
+				adfNode("mediaSingle", nil, adfNode("media", map[string]interface{}{"alt": "screenshot.png"})),
+			),
+			expectedPlain:    "https://example.com/x\n\n[attachment: screenshot.png]",
+			expectedMarkdown: "<https://example.com/x>\n\n[attachment: screenshot.png]",
+		},
+		{
+			name:             "unknown node with content",
+			input:            adfDoc(adfNode("futureNode", nil, adfParagraph(adfText("Still visible")))),
+			expectedPlain:    "Still visible",
+			expectedMarkdown: "Still visible",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedPlain, renderADF(tt.input, ADFPlainText))
+			assert.Equal(t, tt.expectedMarkdown, renderADF(tt.input, ADFMarkdown))
+		})
+	}
+}
+
+func TestGetDescriptionMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    interface{}
+		expected string
+	}{
+		{
+			name:     "nil input",
+			input:    nil,
+			expected: "",
+		},
+		{
+			name:     "plain string description",
+			input:    "simple string",
+			expected: "",
+		},
+		{
+			name:     "map without content",
+			input:    map[string]interface{}{"invalid": "format"},
+			expected: "",
+		},
+		{
+			name: "ADF description",
+			input: adfDoc(
+				adfNode("heading", map[string]interface{}{"level": float64(3)}, adfText("Steps")),
+				adfNode("orderedList", nil, adfNode("listItem", nil, adfParagraph(adfText("Run it")))),
+			),
+			expected: "### Steps\n\n1. Run it",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, getDescriptionMarkdown(tt.input))
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// asOfLayouts lists the accepted formats for the --as-of timestamp, tried in order
+var asOfLayouts = []string{
+	time.RFC3339,
+	JiraTimeFormat,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// parseAsOf parses an --as-of timestamp; values without a zone are taken as UTC
+func parseAsOf(value string) (time.Time, error) {
+	for _, layout := range asOfLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unsupported timestamp format, use RFC 3339 (e.g. 2025-01-31T18:00:00Z)")
+}
+
+// applyAsOf rewrites every task to the state it had at the given instant
+func applyAsOf(response *TransitionCheckResponse, asOf time.Time) {
+	for i, task := range response.Tasks {
+		response.Tasks[i] = reconstructAsOf(task, asOf)
+	}
+	response.AsOf = asOf.Format(JiraTimeFormat)
+}
+
+// reconstructAsOf replays a task's changelog to compute its status and tracked fields at asOf.
+// History recorded after asOf is dropped, since it did not exist at that instant.
+func reconstructAsOf(task JiraTransitionResult, asOf time.Time) JiraTransitionResult {
+	if task.Status == ErrorStatus {
+		return task
+	}
+
+	if created, err := parseJiraTime(task.Created); err == nil && created.After(asOf) {
+		fmt.Fprintf(os.Stderr, "⚠️  JIRA %s was created after %s\n", task.Key, asOf.Format(time.RFC3339))
+	}
+
+	// Status: the source of the first transition after asOf, if any
+	var transitions []Transition
+	for _, transition := range sortedTransitions(task.Transitions) {
+		if happenedAfter(transition.TransitionTime, asOf) {
+			task.Status = transition.FromStatus
+			break
+		}
+		transitions = append(transitions, transition)
+	}
+	if transitions == nil {
+		transitions = []Transition{}
+	}
+	task.Transitions = transitions
+
+	// Tracked fields: the last value set at or before asOf, or the value replaced by the first later change.
+	// Multi-value fields undo the values added and removed after asOf instead.
+	changes := sortedFieldChanges(task.FieldChanges)
+	values := make(map[string]string)
+	decided := make(map[string]bool)
+	multiValueChanges := make(map[string][]FieldChange)
+	var kept []FieldChange
+	for _, change := range changes {
+		field := strings.ToLower(change.Field)
+		if _, ok := multiValueFields[field]; ok {
+			multiValueChanges[field] = append(multiValueChanges[field], change)
+			if !happenedAfter(change.ChangeTime, asOf) {
+				kept = append(kept, change)
+			}
+			continue
+		}
+		if happenedAfter(change.ChangeTime, asOf) {
+			if !decided[field] {
+				values[field] = change.FromValue
+				decided[field] = true
+			}
+			continue
+		}
+		values[field] = change.ToValue
+		kept = append(kept, change)
+	}
+	task.FieldChanges = kept
+	for field, fieldChanges := range multiValueChanges {
+		fieldValues := multiValueAsOf(currentValues(task, field), fieldChanges, asOf, multiValueFields[field])
+		values[field] = strings.Join(fieldValues, ", ")
+		switch field {
+		case "fix version":
+			task.FixVersions = fieldValues
+		case "component":
+			task.Components = fieldValues
+		case "labels":
+			task.Labels = fieldValues
+		}
+	}
+
+	for field, value := range values {
+		switch field {
+		case "assignee":
+			if value == "" {
+				task.Assignee = nil
+			} else {
+				v := value
+				task.Assignee = &v
+			}
+		case "priority":
+			task.Priority = value
+		}
+	}
+
+	if len(values) > 0 {
+		task.FieldValues = make(map[string]string, len(changes))
+		for _, change := range changes {
+			task.FieldValues[change.Field] = values[strings.ToLower(change.Field)]
+		}
+	}
+
+	return task
+}
+
+// asOfFields lists the changelog fields behind top-level ticket fields. --as-of needs their changes to
+// rebuild the ticket, so they are extracted even when --track-fields leaves them out.
+var asOfFields = []string{"assignee", "priority", "Fix Version", "Component", "labels"}
+
+// withAsOfFields returns the tracked fields extended by the asOfFields not already tracked (case-insensitive)
+func withAsOfFields(trackedFields []string) []string {
+	fields := append([]string(nil), trackedFields...)
+	for _, field := range asOfFields {
+		tracked := false
+		for _, existing := range fields {
+			if strings.EqualFold(existing, field) {
+				tracked = true
+				break
+			}
+		}
+		if !tracked {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// multiValueFields lists the tracked fields (lowercase) JIRA records as one changelog item per added or removed
+// value, mapped to whether an item may carry several space-separated values, as labels do
+var multiValueFields = map[string]bool{
+	"fix version": false,
+	"component":   false,
+	"labels":      true,
+}
+
+// currentValues returns the values a task has today for a multi-value field
+func currentValues(task JiraTransitionResult, field string) []string {
+	switch field {
+	case "fix version":
+		return task.FixVersions
+	case "component":
+		return task.Components
+	case "labels":
+		return task.Labels
+	}
+	return nil
+}
+
+// multiValueAsOf returns the values of a multi-value field at asOf: starting from the current values,
+// the changes made after asOf are undone, latest first. Values put back are appended.
+func multiValueAsOf(current []string, changes []FieldChange, asOf time.Time, spaceSeparated bool) []string {
+	split := func(value string) []string {
+		if spaceSeparated {
+			return strings.Fields(value)
+		}
+		if value == "" {
+			return nil
+		}
+		return []string{value}
+	}
+
+	values := append([]string(nil), current...)
+	for i := len(changes) - 1; i >= 0; i-- {
+		change := changes[i]
+		if !happenedAfter(change.ChangeTime, asOf) {
+			continue
+		}
+		added := split(change.ToValue)
+		var remaining []string
+		for _, value := range values {
+			if !containsString(added, value) {
+				remaining = append(remaining, value)
+			}
+		}
+		values = remaining
+		for _, value := range split(change.FromValue) {
+			if !containsString(values, value) {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// happenedAfter reports whether a JIRA timestamp lies strictly after t; unparseable values are kept
+func happenedAfter(value string, t time.Time) bool {
+	at, err := parseJiraTime(value)
+	return err == nil && at.After(t)
+}
+
+// sortedFieldChanges returns the field changes ordered by change time (stable for equal times)
+func sortedFieldChanges(changes []FieldChange) []FieldChange {
+	times := make([]string, len(changes))
+	for i, change := range changes {
+		times[i] = change.ChangeTime
+	}
+	var sorted []FieldChange
+	for _, i := range timeOrder(times) {
+		sorted = append(sorted, changes[i])
+	}
+	return sorted
+}
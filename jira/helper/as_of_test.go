@@ -0,0 +1,277 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAsOf(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    time.Time
+		expectError bool
+	}{
+		{
+			name:     "RFC 3339",
+			input:    "2025-01-31T18:00:00Z",
+			expected: time.Date(2025, 1, 31, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "RFC 3339 with offset",
+			input:    "2025-01-31T20:00:00+02:00",
+			expected: time.Date(2025, 1, 31, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "JIRA format",
+			input:    "2025-01-31T18:00:00.000+0000",
+			expected: time.Date(2025, 1, 31, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Date only",
+			input:    "2025-01-31",
+			expected: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "Invalid value",
+			input:       "yesterday",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseAsOf(tt.input)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, tt.expected.Equal(result), "expected %v, got %v", tt.expected, result)
+		})
+	}
+}
+
+func TestReconstructAsOf(t *testing.T) {
+	task := JiraTransitionResult{
+		Key:         "EV-1",
+		Status:      "Done",
+		Created:     "2025-01-01T10:00:00.000+0000",
+		Assignee:    strPtr("Carol"),
+		Priority:    "Highest",
+		FixVersions: []string{"1.2.0"},
+		Transitions: []Transition{
+			{FromStatus: "To Do", ToStatus: "In Progress", TransitionTime: "2025-01-02T10:00:00.000+0000"},
+			{FromStatus: "In Progress", ToStatus: "In Review", TransitionTime: "2025-01-04T10:00:00.000+0000"},
+			{FromStatus: "In Review", ToStatus: "Done", TransitionTime: "2025-01-06T10:00:00.000+0000"},
+		},
+		FieldChanges: []FieldChange{
+			{Field: "assignee", FromValue: "", ToValue: "Alice", ChangeTime: "2025-01-02T09:00:00.000+0000"},
+			{Field: "assignee", FromValue: "Alice", ToValue: "Bob", ChangeTime: "2025-01-05T09:00:00.000+0000"},
+			{Field: "assignee", FromValue: "Bob", ToValue: "Carol", ChangeTime: "2025-01-07T09:00:00.000+0000"},
+			{Field: "priority", FromValue: "Medium", ToValue: "Highest", ChangeTime: "2025-01-08T09:00:00.000+0000"},
+			{Field: "Fix Version", FromValue: "", ToValue: "1.2.0", ChangeTime: "2025-01-03T09:00:00.000+0000"},
+		},
+	}
+
+	tests := []struct {
+		name                string
+		asOf                time.Time
+		expectedStatus      string
+		expectedAssignee    *string
+		expectedPriority    string
+		expectedTransitions int
+		expectedChanges     int
+		expectedValues      map[string]string
+	}{
+		{
+			name:                "Before any change",
+			asOf:                time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+			expectedStatus:      "To Do",
+			expectedAssignee:    nil,
+			expectedPriority:    "Medium",
+			expectedTransitions: 0,
+			expectedChanges:     0,
+			expectedValues:      map[string]string{"assignee": "", "priority": "Medium", "Fix Version": ""},
+		},
+		{
+			name:                "Mid-review",
+			asOf:                time.Date(2025, 1, 5, 12, 0, 0, 0, time.UTC),
+			expectedStatus:      "In Review",
+			expectedAssignee:    strPtr("Bob"),
+			expectedPriority:    "Medium",
+			expectedTransitions: 2,
+			expectedChanges:     3,
+			expectedValues:      map[string]string{"assignee": "Bob", "priority": "Medium", "Fix Version": "1.2.0"},
+		},
+		{
+			name:                "After all changes",
+			asOf:                time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+			expectedStatus:      "Done",
+			expectedAssignee:    strPtr("Carol"),
+			expectedPriority:    "Highest",
+			expectedTransitions: 3,
+			expectedChanges:     5,
+			expectedValues:      map[string]string{"assignee": "Carol", "priority": "Highest", "Fix Version": "1.2.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := reconstructAsOf(task, tt.asOf)
+
+			assert.Equal(t, tt.expectedStatus, result.Status)
+			assert.Equal(t, tt.expectedAssignee, result.Assignee)
+			assert.Equal(t, tt.expectedPriority, result.Priority)
+			assert.Len(t, result.Transitions, tt.expectedTransitions)
+			assert.Len(t, result.FieldChanges, tt.expectedChanges)
+			assert.Equal(t, tt.expectedValues, result.FieldValues)
+		})
+	}
+
+	// The original task is left untouched
+	assert.Equal(t, "Done", task.Status)
+	assert.Len(t, task.Transitions, 3)
+}
+
+func TestReconstructAsOfMultiValueFields(t *testing.T) {
+	task := JiraTransitionResult{
+		Key:         "EV-3",
+		Status:      "Done",
+		Labels:      []string{"backend", "security"},
+		FixVersions: []string{"1.2.0", "Release 1.2 LTS"},
+		Transitions: []Transition{},
+		FieldChanges: []FieldChange{
+			// 1.1.0 was set when the ticket was created
+			{Field: "Fix Version", FromValue: "1.1.0", ToValue: "", ChangeTime: "2025-01-04T09:00:00.000+0000"},
+			{Field: "Fix Version", FromValue: "", ToValue: "1.2.0", ChangeTime: "2025-01-02T09:00:00.000+0000"},
+			{Field: "Fix Version", FromValue: "", ToValue: "Release 1.2 LTS", ChangeTime: "2025-01-02T09:00:00.000+0000"},
+			{Field: "labels", FromValue: "", ToValue: "backend", ChangeTime: "2025-01-02T09:00:00.000+0000"},
+			{Field: "labels", FromValue: "backend", ToValue: "backend security", ChangeTime: "2025-01-03T09:00:00.000+0000"},
+		},
+	}
+
+	tests := []struct {
+		name                string
+		asOf                time.Time
+		expected            map[string]string
+		expectedLabels      []string
+		expectedFixVersions []string
+	}{
+		{
+			name:                "Before any change",
+			asOf:                time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected:            map[string]string{"Fix Version": "1.1.0", "labels": ""},
+			expectedFixVersions: []string{"1.1.0"},
+		},
+		{
+			name:                "Two versions added",
+			asOf:                time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC),
+			expected:            map[string]string{"Fix Version": "1.2.0, Release 1.2 LTS, 1.1.0", "labels": "backend"},
+			expectedLabels:      []string{"backend"},
+			expectedFixVersions: []string{"1.2.0", "Release 1.2 LTS", "1.1.0"},
+		},
+		{
+			name:                "After all changes",
+			asOf:                time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+			expected:            map[string]string{"Fix Version": "1.2.0, Release 1.2 LTS", "labels": "backend, security"},
+			expectedLabels:      []string{"backend", "security"},
+			expectedFixVersions: []string{"1.2.0", "Release 1.2 LTS"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := reconstructAsOf(task, tt.asOf)
+			assert.Equal(t, tt.expected, result.FieldValues)
+			// The top-level fields agree with the field values
+			assert.Equal(t, tt.expectedLabels, result.Labels)
+			assert.Equal(t, tt.expectedFixVersions, result.FixVersions)
+		})
+	}
+
+	t.Run("Value set at creation", func(t *testing.T) {
+		task := JiraTransitionResult{
+			Key:         "EV-4",
+			Status:      "Done",
+			Components:  []string{"Pipeline", "Reports"},
+			FixVersions: []string{"1.0", "2.0"},
+			Transitions: []Transition{},
+			FieldChanges: []FieldChange{
+				{Field: "Fix Version", FromValue: "", ToValue: "2.0", ChangeTime: "2025-01-05T09:00:00.000+0000"},
+				{Field: "Component", FromValue: "", ToValue: "Reports", ChangeTime: "2025-01-05T09:00:00.000+0000"},
+			},
+		}
+		result := reconstructAsOf(task, time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC))
+		assert.Equal(t, map[string]string{"Fix Version": "1.0", "Component": "Pipeline"}, result.FieldValues)
+		assert.Equal(t, []string{"1.0"}, result.FixVersions)
+		assert.Equal(t, []string{"Pipeline"}, result.Components)
+	})
+}
+
+func TestReconstructAsOfErrorResult(t *testing.T) {
+	task := JiraTransitionResult{Key: "EV-2", Status: ErrorStatus, Transitions: []Transition{}}
+	result := reconstructAsOf(task, time.Now())
+	assert.Equal(t, task, result)
+}
+
+func TestApplyAsOf(t *testing.T) {
+	asOf := time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)
+	response := TransitionCheckResponse{
+		Tasks: []JiraTransitionResult{
+			{
+				Key:     "EV-1",
+				Status:  "Done",
+				Created: "2025-01-01T10:00:00.000+0000",
+				Transitions: []Transition{
+					{FromStatus: "To Do", ToStatus: "Done", TransitionTime: "2025-01-04T10:00:00.000+0000"},
+				},
+			},
+		},
+	}
+
+	applyAsOf(&response, asOf)
+
+	require.Len(t, response.Tasks, 1)
+	assert.Equal(t, "To Do", response.Tasks[0].Status)
+	assert.Empty(t, response.Tasks[0].Transitions)
+	assert.Equal(t, "2025-01-03T00:00:00.000+0000", response.AsOf)
+}
+
+func TestEnrichResponseUsesAsOfForMetrics(t *testing.T) {
+	config := &AppConfig{
+		DoneStatuses:       parseFieldList(DefaultDoneStatuses),
+		InProgressStatuses: parseFieldList(DefaultInProgressStatuses),
+		AsOf:               time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC),
+	}
+	response := TransitionCheckResponse{
+		Tasks: []JiraTransitionResult{
+			{Key: "EV-1", Status: "To Do", Created: "2025-01-01T10:00:00.000+0000"},
+		},
+	}
+
+	enrichResponse(&response, config, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	require.NotNil(t, response.Tasks[0].Metrics)
+	assert.Equal(t, []StatusDuration{{Status: "To Do", Hours: 24}}, response.Tasks[0].Metrics.TimeInStatus)
+}
+
+func TestSortedFieldChanges(t *testing.T) {
+	changes := []FieldChange{
+		{ToValue: "Carol", ChangeTime: "2025-01-03T10:00:00.000+0000"},
+		{ToValue: "Imported", ChangeTime: "not a timestamp"},
+		{ToValue: "Alice", ChangeTime: "2025-01-01T10:00:00.000+0000"},
+		{ToValue: "Migrated", ChangeTime: ""},
+		{ToValue: "Bob", ChangeTime: "2025-01-02T10:00:00.000+0000"},
+	}
+
+	var values []string
+	for _, change := range sortedFieldChanges(changes) {
+		values = append(values, change.ToValue)
+	}
+	// Unparseable times follow the others in their original order
+	assert.Equal(t, []string{"Alice", "Bob", "Carol", "Imported", "Migrated"}, values)
+}
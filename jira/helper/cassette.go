@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Constants for HTTP cassettes
+const (
+	// CassetteVersion is the format version written to new cassettes
+	CassetteVersion = 1
+	// replayCredential stands in for the username and token while replaying; the cassette ignores them
+	replayCredential = "replay"
+)
+
+// cassetteHeaders are the only response headers kept in a cassette; everything else may carry session data
+var cassetteHeaders = []string{"Content-Type", "Retry-After"}
+
+// Cassette holds the JIRA API exchanges of one run, scrubbed of credentials, for deterministic replay
+type Cassette struct {
+	Version int `json:"version"`
+	// JIRAURL is the instance the exchanges were recorded against, so replayed links match the original run
+	JIRAURL string `json:"jira_url"`
+	// RecordedAt is used as the current time when replaying, so time-dependent metrics are reproduced
+	RecordedAt   time.Time             `json:"recorded_at"`
+	Interactions []CassetteInteraction `json:"interactions"`
+}
+
+// CassetteInteraction is one recorded request and its response
+type CassetteInteraction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// CassetteRequest identifies a request; headers, including Authorization, are never recorded
+type CassetteRequest struct {
+	Method string `json:"method"`
+	// URL is the path and query, without scheme and host
+	URL string `json:"url"`
+}
+
+// CassetteResponse is a recorded response
+type CassetteResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// loadCassette reads a cassette file
+func loadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &ValidationError{Field: "replay", Value: path, Err: err}
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, &ValidationError{Field: "replay", Value: path, Err: fmt.Errorf("invalid cassette: %v", err)}
+	}
+	if cassette.Version != CassetteVersion {
+		return nil, &ValidationError{Field: "replay", Value: path, Err: fmt.Errorf("unsupported cassette version %d", cassette.Version)}
+	}
+	return &cassette, nil
+}
+
+// saveCassette writes a cassette file
+func saveCassette(path string, cassette *Cassette) error {
+	data, err := json.MarshalIndent(cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling cassette: %v", err)
+	}
+	if err := writeToFile(path, data); err != nil {
+		return fmt.Errorf("error writing cassette: %v", err)
+	}
+	fmt.Printf("Recorded %d JIRA API exchanges to: %s\n", len(cassette.Interactions), path)
+	return nil
+}
+
+// cassetteRecorder is an http.RoundTripper that records every exchange passing through it.
+// It sits below the authentication transport, but only the method and URL of requests are kept,
+// and response bodies are redacted before they are stored.
+type cassetteRecorder struct {
+	next     http.RoundTripper
+	mu       sync.Mutex
+	cassette *Cassette
+}
+
+func newCassetteRecorder(next http.RoundTripper, jiraURL string, now time.Time) *cassetteRecorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &cassetteRecorder{
+		next: next,
+		cassette: &Cassette{
+			Version:      CassetteVersion,
+			JIRAURL:      jiraURL,
+			RecordedAt:   now.UTC().Truncate(time.Second),
+			Interactions: []CassetteInteraction{},
+		},
+	}
+}
+
+// RoundTrip sends the request and records the exchange; transport errors are passed through unrecorded
+func (r *cassetteRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	header := http.Header{}
+	for _, name := range cassetteHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			header.Set(name, value)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, CassetteInteraction{
+		Request:  CassetteRequest{Method: req.Method, URL: redact(req.URL.RequestURI())},
+		Response: CassetteResponse{StatusCode: resp.StatusCode, Header: header, Body: redact(string(body))},
+	})
+	return resp, nil
+}
+
+// cassettePlayer is an http.RoundTripper that answers from a cassette without any network access.
+// Each interaction is used once, in recorded order among requests with the same method and URL.
+type cassettePlayer struct {
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+func newCassettePlayer(cassette *Cassette) *cassettePlayer {
+	return &cassettePlayer{cassette: cassette, used: make([]bool, len(cassette.Interactions))}
+}
+
+// RoundTrip returns the next unused recorded response for the request
+func (p *cassettePlayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	uri := redact(req.URL.RequestURI())
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, interaction := range p.cassette.Interactions {
+		if p.used[i] || interaction.Request.Method != req.Method || interaction.Request.URL != uri {
+			continue
+		}
+		p.used[i] = true
+
+		recorded := interaction.Response
+		header := recorded.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+			StatusCode:    recorded.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+			ContentLength: int64(len(recorded.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("cassette has no recorded response for %s %s", req.Method, uri)
+}
+
+// fetchJiraResponse fetches and enriches config.JIRAIDs, recording the JIRA exchanges to config.RecordFile
+// or answering them from config.ReplayFile when set
+func fetchJiraResponse(config *AppConfig, now time.Time) (TransitionCheckResponse, error) {
+	return runJiraSession(config, now, func(client *JiraClient) TransitionCheckResponse {
+		return client.FetchJiraDetails(config.JIRAIDs)
+	})
+}
+
+// runJiraSession builds the JIRA client for config, honouring --record and --replay, and enriches the response
+// that fetch collects with it
+func runJiraSession(config *AppConfig, now time.Time, fetch func(client *JiraClient) TransitionCheckResponse) (TransitionCheckResponse, error) {
+	var recorder *cassetteRecorder
+
+	switch {
+	case config.ReplayFile != "":
+		cassette, err := loadCassette(config.ReplayFile)
+		if err != nil {
+			return TransitionCheckResponse{}, err
+		}
+		fmt.Printf("Replaying JIRA API exchanges from: %s\n", config.ReplayFile)
+		replayConfig := *config
+		replayConfig.JIRAURL = cassette.JIRAURL
+		replayConfig.JIRAUsername = replayCredential
+		replayConfig.JIRAToken = replayCredential
+		replayConfig.HTTPClient = &http.Client{Transport: newCassettePlayer(cassette)}
+		config = &replayConfig
+		now = cassette.RecordedAt
+
+	case config.RecordFile != "":
+		// Tokens set without resolveCredentials (tests, embedding) must be scrubbed from the cassette too
+		registerSecret(config.JIRAToken)
+		recordConfig := *config
+		httpClient := &http.Client{}
+		if config.HTTPClient != nil {
+			copied := *config.HTTPClient
+			httpClient = &copied
+		}
+		recorder = newCassetteRecorder(httpClient.Transport, strings.TrimRight(config.JIRAURL, "/"), now)
+		httpClient.Transport = recorder
+		recordConfig.HTTPClient = httpClient
+		config = &recordConfig
+		// Compute metrics at the recorded instant so a replay reproduces this run exactly
+		now = recorder.cassette.RecordedAt
+	}
+
+	jiraClient, err := NewJiraClientFromConfig(config)
+	if err != nil {
+		return TransitionCheckResponse{}, fmt.Errorf("error creating JIRA client: %v", err)
+	}
+
+	response := fetch(jiraClient)
+	enrichResponse(&response, config, now)
+
+	if recorder != nil {
+		if err := saveCassette(config.RecordFile, recorder.cassette); err != nil {
+			return response, err
+		}
+	}
+	return response, nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cassetteNow is the time of the recorded run; EV-2 is still in progress then, so its metrics depend on it
+var cassetteNow = time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	resetSecrets(t)
+	fake, server := newFakeJiraServer(t, "ci@example.com", "cassette-secret-token")
+	dir := t.TempDir()
+	cassettePath := filepath.Join(dir, "cassette.json")
+
+	// Record a run against the fake JIRA
+	recordConfig := &AppConfig{
+		JIRAURL:       server.URL,
+		JIRAUsername:  "ci@example.com",
+		JIRAToken:     "cassette-secret-token",
+		JIRAIDs:       []string{"EV-1", "EV-2", "EV-404", "EV-429"},
+		TrackedFields: []string{"assignee"},
+		RecordFile:    cassettePath,
+	}
+	recorded, err := fetchJiraResponse(recordConfig, cassetteNow)
+	require.NoError(t, err)
+	require.Len(t, recorded.Tasks, 4)
+	assert.Len(t, fake.Requests(), 4)
+
+	data, err := os.ReadFile(cassettePath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "cassette-secret-token")
+	assert.NotContains(t, string(data), "Authorization")
+
+	cassette, err := loadCassette(cassettePath)
+	require.NoError(t, err)
+	assert.Equal(t, server.URL, cassette.JIRAURL)
+	assert.Equal(t, cassetteNow, cassette.RecordedAt)
+	require.Len(t, cassette.Interactions, 4)
+	assert.Equal(t, CassetteRequest{Method: http.MethodGet, URL: "/rest/api/2/issue/EV-1?expand=changelog"}, cassette.Interactions[0].Request)
+	assert.Equal(t, http.StatusTooManyRequests, cassette.Interactions[3].Response.StatusCode)
+	assert.Equal(t, "1", cassette.Interactions[3].Response.Header.Get("Retry-After"))
+
+	// Replay without credentials or network reproduces the recorded run exactly
+	server.Close()
+	replayConfig := &AppConfig{
+		JIRAIDs:       recordConfig.JIRAIDs,
+		TrackedFields: recordConfig.TrackedFields,
+		ReplayFile:    cassettePath,
+	}
+	replayed, err := fetchJiraResponse(replayConfig, cassetteNow.AddDate(1, 0, 0))
+	require.NoError(t, err)
+	assert.Equal(t, recorded, replayed)
+}
+
+func TestCassettePlayer(t *testing.T) {
+	cassette := &Cassette{
+		Version: CassetteVersion,
+		Interactions: []CassetteInteraction{
+			{Request: CassetteRequest{Method: "GET", URL: "/rest/api/2/field"}, Response: CassetteResponse{StatusCode: 500, Body: "first"}},
+			{Request: CassetteRequest{Method: "GET", URL: "/rest/api/2/field"}, Response: CassetteResponse{StatusCode: 200, Body: "second"}},
+		},
+	}
+	client := &http.Client{Transport: newCassettePlayer(cassette)}
+
+	// Repeated requests are answered in recorded order, each interaction once
+	for _, expected := range []string{"first", "second"} {
+		resp, err := client.Get("https://example.atlassian.net/rest/api/2/field")
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(body))
+	}
+
+	_, err := client.Get("https://example.atlassian.net/rest/api/2/field")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cassette has no recorded response for GET /rest/api/2/field")
+}
+
+func TestLoadCassette(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	tests := []struct {
+		name          string
+		path          string
+		errorContains string
+	}{
+		{name: "Valid", path: write("valid.json", `{"version": 1, "jira_url": "https://example.atlassian.net", "interactions": []}`)},
+		{name: "Missing file", path: filepath.Join(dir, "missing.json"), errorContains: "no such file"},
+		{name: "Invalid JSON", path: write("invalid.json", `{"version":`), errorContains: "invalid cassette"},
+		{name: "Unknown version", path: write("v2.json", `{"version": 2}`), errorContains: "unsupported cassette version 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadCassette(tt.path)
+			if tt.errorContains == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			var validationErr *ValidationError
+			require.True(t, errors.As(err, &validationErr))
+			assert.Equal(t, "replay", validationErr.Field)
+			assert.Contains(t, err.Error(), tt.errorContains)
+		})
+	}
+}
+
+func TestLoadConfigCassetteFlags(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv("JIRA_API_TOKEN_FILE", "")
+	t.Setenv("JIRA_CREDENTIAL_HELPER", "")
+	t.Setenv("NETRC", filepath.Join(t.TempDir(), "no-netrc"))
+
+	// Replaying needs no credentials
+	config, err := LoadConfig(&FlagConfig{Replay: "cassette.json"}, []string{"EV-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "cassette.json", config.ReplayFile)
+
+	_, err = LoadConfig(&FlagConfig{Record: "out.json", Replay: "cassette.json"}, []string{"EV-1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot be combined with --record")
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Changelog constants
+const (
+	// DefaultChangelogFile is the changelog updated when no -o is given
+	DefaultChangelogFile = "CHANGELOG.md"
+
+	// Keep a Changelog categories the issue types map to
+	ChangelogAdded    = "Added"
+	ChangelogChanged  = "Changed"
+	ChangelogFixed    = "Fixed"
+	ChangelogSecurity = "Security"
+
+	// changelogSecurityLabel files a ticket of any type under Security
+	changelogSecurityLabel = "security"
+
+	// changelogHeader starts a changelog that does not exist yet
+	changelogHeader = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+`
+)
+
+// changelogCategories maps issue types (case-insensitive) to Keep a Changelog categories, in the order they are
+// shown. Types no category claims are listed under Changed.
+var changelogCategories = []struct {
+	Title string
+	Types []string
+}{
+	{Title: ChangelogAdded, Types: []string{"Story", "Feature", "New Feature", "Epic"}},
+	{Title: ChangelogChanged, Types: []string{"Improvement", "Task", "Sub-task", "Subtask", "Chore"}},
+	{Title: ChangelogFixed, Types: []string{"Bug", "Defect"}},
+	{Title: ChangelogSecurity, Types: []string{"Security", "Vulnerability"}},
+}
+
+// changelogVersionPattern matches version headings such as "## [1.4.0] - 2025-01-03", "## [Unreleased]" or
+// "## v1.4.0"; other level-two headings such as "## Notes" are not versions
+var changelogVersionPattern = regexp.MustCompile(`^##\s+(?:\[([^\]]+)\]|(v?\d+\.\d+\.\d+\S*))`)
+
+// changelogCategory returns the category a ticket is listed under; the security label wins over the issue type
+func changelogCategory(task JiraTransitionResult) string {
+	if hasAnyLabel(task, []string{changelogSecurityLabel}) {
+		return ChangelogSecurity
+	}
+	for _, category := range changelogCategories {
+		if statusIn(task.Type, category.Types) {
+			return category.Title
+		}
+	}
+	return ChangelogChanged
+}
+
+// changelogVersion normalizes a version or tag for a changelog heading, e.g. v1.4.0 becomes 1.4.0
+func changelogVersion(version string) string {
+	if len(version) > 1 && (version[0] == 'v' || version[0] == 'V') && version[1] >= '0' && version[1] <= '9' {
+		return version[1:]
+	}
+	return version
+}
+
+// generateChangelogSection renders release notes grouped by change type as a Keep a Changelog version section
+func generateChangelogSection(notes releaseNotes) string {
+	var sb strings.Builder
+	version := changelogVersion(notes.Version)
+	if version == UnreleasedVersion || notes.Date.IsZero() {
+		sb.WriteString(fmt.Sprintf("## [%s]\n", version))
+	} else {
+		sb.WriteString(fmt.Sprintf("## [%s] - %s\n", version, notes.Date.Format("2006-01-02")))
+	}
+
+	for _, group := range notes.Groups {
+		sb.WriteString(fmt.Sprintf("\n### %s\n\n", group.Title))
+		for _, entry := range group.Entries {
+			key := linkify(entry.Key, entry.Link)
+			if entry.Summary == "" {
+				sb.WriteString(fmt.Sprintf("- %s\n", key))
+			} else {
+				sb.WriteString(fmt.Sprintf("- %s (%s)\n", entry.Summary, key))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// insertChangelogSection inserts a version section into a changelog and reports whether it did.
+// The section goes above the newest released version, below an [Unreleased] section, or after the header when the
+// changelog has no versions yet. A changelog that already has a section for the version is returned unchanged,
+// and everything around the inserted section is kept byte for byte.
+func insertChangelogSection(changelog, version, section string) (string, bool) {
+	if changelog == "" {
+		changelog = changelogHeader
+	}
+	newline := "\n"
+	if strings.Contains(changelog, "\r\n") {
+		newline = "\r\n"
+		section = strings.ReplaceAll(section, "\n", newline)
+	}
+
+	lines := strings.SplitAfter(changelog, "\n")
+	insertAt := -1
+	for i, line := range lines {
+		match := changelogVersionPattern.FindStringSubmatch(strings.TrimRight(line, "\r\n"))
+		if match == nil {
+			continue
+		}
+		existing := changelogVersion(match[1] + match[2])
+		if strings.EqualFold(existing, version) {
+			return changelog, false
+		}
+		if insertAt < 0 && !strings.EqualFold(existing, UnreleasedVersion) {
+			insertAt = i
+		}
+	}
+
+	if insertAt < 0 {
+		// No released version yet: append, separated from the preceding content by a blank line
+		before := changelog
+		if !strings.HasSuffix(before, newline) {
+			before += newline
+		}
+		if !strings.HasSuffix(before, newline+newline) {
+			before += newline
+		}
+		return before + section, true
+	}
+
+	before := strings.Join(lines[:insertAt], "")
+	if insertAt > 0 && strings.TrimSpace(lines[insertAt-1]) != "" {
+		before += newline
+	}
+	return before + section + newline + strings.Join(lines[insertAt:], ""), true
+}
+
+// runChangelogCommand adds a version section built from an evidence file to a Keep a Changelog file
+func runChangelogCommand(flags *FlagConfig, args []string, config *AppConfig) error {
+	changelogFile := getOrDefault(flags.ChangelogFile, DefaultChangelogFile)
+
+	response, _, err := loadTransitionResponse(resolveInputFile(flags, config))
+	if err != nil {
+		return err
+	}
+
+	notes := buildReleaseNotes(response, releaseNotesOptions{
+		Version:       releaseNotesVersion(flags.Version, NewGitService()),
+		Date:          config.now(),
+		GroupBy:       []string{groupByChangeType},
+		ExcludeLabels: parseFieldList(getOrDefault(flags.ExcludeLabels, DefaultExcludeLabels)),
+	})
+	version := changelogVersion(notes.Version)
+
+	existing, err := os.ReadFile(changelogFile)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error reading changelog: %v", err)
+	}
+
+	updated, inserted := insertChangelogSection(string(existing), version, generateChangelogSection(notes))
+	if !inserted {
+		fmt.Printf("%s already has a section for %s, left unchanged\n", changelogFile, version)
+		return nil
+	}
+	if err := writeToFile(changelogFile, []byte(updated)); err != nil {
+		return fmt.Errorf("error writing to file: %v", err)
+	}
+
+	count := 0
+	for _, group := range notes.Groups {
+		count += len(group.Entries)
+	}
+	fmt.Printf("Added %s to %s with %d tickets\n", version, changelogFile, count)
+	if len(notes.Excluded) > 0 {
+		fmt.Printf("Excluded by label: %s\n", strings.Join(notes.Excluded, ", "))
+	}
+	if len(notes.Skipped) > 0 {
+		fmt.Printf("Skipped, could not be fetched: %s\n", strings.Join(notes.Skipped, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangelogCategory(t *testing.T) {
+	tests := []struct {
+		task     JiraTransitionResult
+		expected string
+	}{
+		{task: JiraTransitionResult{Type: "Story"}, expected: ChangelogAdded},
+		{task: JiraTransitionResult{Type: "new feature"}, expected: ChangelogAdded},
+		{task: JiraTransitionResult{Type: "Task"}, expected: ChangelogChanged},
+		{task: JiraTransitionResult{Type: "Spike"}, expected: ChangelogChanged},
+		{task: JiraTransitionResult{Type: "Bug"}, expected: ChangelogFixed},
+		{task: JiraTransitionResult{Type: "Vulnerability"}, expected: ChangelogSecurity},
+		// The security label wins over the issue type
+		{task: JiraTransitionResult{Type: "Bug", Labels: []string{"Security"}}, expected: ChangelogSecurity},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, changelogCategory(tt.task), tt.task.Type)
+	}
+}
+
+func TestChangelogVersion(t *testing.T) {
+	assert.Equal(t, "1.4.0", changelogVersion("v1.4.0"))
+	assert.Equal(t, "1.4.0", changelogVersion("1.4.0"))
+	assert.Equal(t, "very-first", changelogVersion("very-first"))
+	assert.Equal(t, UnreleasedVersion, changelogVersion(UnreleasedVersion))
+}
+
+func TestGenerateChangelogSection(t *testing.T) {
+	opts := releaseNotesOptions{
+		Version:       "v1.4.0",
+		Date:          time.Date(2025, 1, 3, 9, 0, 0, 0, time.UTC),
+		GroupBy:       []string{groupByChangeType},
+		ExcludeLabels: []string{DefaultExcludeLabels},
+	}
+	response := TransitionCheckResponse{Tasks: []JiraTransitionResult{
+		{Key: "EV-10", Link: "https://example.atlassian.net/browse/EV-10", Summary: "Sign release artifacts", Type: "Story"},
+		{Key: "EV-2", Summary: "Fix report encoding", Type: "bug"},
+		{Key: "EV-3", Summary: "Bump linters", Type: "Task", Labels: []string{"No-Release-Notes"}},
+		{Key: "EV-9", Summary: "", Type: "Spike"},
+		{Key: "OPS-1", Summary: "Rotate deploy keys", Type: "Task"},
+		{Key: "EV-12", Summary: "Stop logging tokens", Type: "Bug", Labels: []string{"security"}},
+		{Key: "EV-404", Status: ErrorStatus, Type: ErrorType, Error: &TaskError{Code: ErrorCodeNotFound}},
+	}}
+
+	assert.Equal(t, `## [1.4.0] - 2025-01-03
+
+### Added
+
+- Sign release artifacts ([EV-10](https://example.atlassian.net/browse/EV-10))
+
+### Changed
+
+- EV-9
+- Rotate deploy keys (OPS-1)
+
+### Fixed
+
+- Fix report encoding (EV-2)
+
+### Security
+
+- Stop logging tokens (EV-12)
+`, generateChangelogSection(buildReleaseNotes(response, opts)))
+
+	unreleased := buildReleaseNotes(TransitionCheckResponse{}, releaseNotesOptions{Date: opts.Date, GroupBy: opts.GroupBy})
+	assert.Equal(t, "## [Unreleased]\n", generateChangelogSection(unreleased))
+}
+
+func TestInsertChangelogSection(t *testing.T) {
+	section := "## [1.4.0] - 2025-01-03\n\n### Fixed\n\n- Fix report encoding (EV-2)\n"
+
+	tests := []struct {
+		name      string
+		changelog string
+		expected  string
+		inserted  bool
+	}{
+		{
+			name:      "New changelog gets the header",
+			changelog: "",
+			expected:  changelogHeader + "\n" + section,
+			inserted:  true,
+		},
+		{
+			name: "Above the newest release, below Unreleased",
+			changelog: "# Changelog\n\n## [Unreleased]\n\n### Added\n\n- Work in progress\n\n" +
+				"## [1.3.0] - 2024-12-01\n\n### Added\n\n- Merge command\n\n[1.3.0]: https://example.com/compare/v1.2.0...v1.3.0\n",
+			expected: "# Changelog\n\n## [Unreleased]\n\n### Added\n\n- Work in progress\n\n" + section + "\n" +
+				"## [1.3.0] - 2024-12-01\n\n### Added\n\n- Merge command\n\n[1.3.0]: https://example.com/compare/v1.2.0...v1.3.0\n",
+			inserted: true,
+		},
+		{
+			name:      "Header without versions and without a trailing newline",
+			changelog: "# Changelog",
+			expected:  "# Changelog\n\n" + section,
+			inserted:  true,
+		},
+		{
+			name:      "Heading directly after text",
+			changelog: "# Changelog\n## 1.3.0\n- Merge command\n",
+			expected:  "# Changelog\n\n" + section + "\n## 1.3.0\n- Merge command\n",
+			inserted:  true,
+		},
+		{
+			name:      "Headings that are not versions are skipped",
+			changelog: "# Changelog\n\n## Notes\n\nSee the docs.\n\n## [1.3.0] - 2024-12-01\n\n- Merge command\n",
+			expected:  "# Changelog\n\n## Notes\n\nSee the docs.\n\n" + section + "\n## [1.3.0] - 2024-12-01\n\n- Merge command\n",
+			inserted:  true,
+		},
+		{
+			name:      "Existing version is left alone",
+			changelog: "# Changelog\n\n## [v1.4.0] - 2025-01-02\n\n- Hand-written notes\n",
+			expected:  "# Changelog\n\n## [v1.4.0] - 2025-01-02\n\n- Hand-written notes\n",
+			inserted:  false,
+		},
+		{
+			name:      "Windows line endings are kept",
+			changelog: "# Changelog\r\n\r\n## [1.3.0]\r\n",
+			expected:  "# Changelog\r\n\r\n## [1.4.0] - 2025-01-03\r\n\r\n### Fixed\r\n\r\n- Fix report encoding (EV-2)\r\n\r\n## [1.3.0]\r\n",
+			inserted:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated, inserted := insertChangelogSection(tt.changelog, "1.4.0", section)
+			assert.Equal(t, tt.inserted, inserted)
+			assert.Equal(t, tt.expected, updated)
+		})
+	}
+}
+
+func TestRunChangelogCommand(t *testing.T) {
+	dir := t.TempDir()
+	inputFile := filepath.Join(dir, "evidence.json")
+	response := TransitionCheckResponse{Tasks: []JiraTransitionResult{
+		{Key: "EV-10", Link: "https://example.atlassian.net/browse/EV-10", Summary: "Sign release artifacts", Type: "Story"},
+		{Key: "EV-2", Summary: "Fix report encoding", Type: "Bug"},
+		{Key: "EV-3", Summary: "Bump linters", Type: "Task", Labels: []string{"No-Release-Notes"}},
+	}}
+	require.NoError(t, saveJiraResults(response, &AppConfig{OutputFile: inputFile}))
+
+	changelogFile := filepath.Join(dir, "CHANGELOG.md")
+	original := changelogHeader + "\n## [1.3.0] - 2024-12-01\n\n### Added\n\n- Merge command\n"
+	require.NoError(t, os.WriteFile(changelogFile, []byte(original), 0644))
+
+	config := &AppConfig{Clock: func() time.Time { return time.Date(2025, 1, 3, 9, 0, 0, 0, time.UTC) }}
+	flags := &FlagConfig{InputFile: inputFile, ChangelogFile: changelogFile, Version: "v1.4.0"}
+	require.NoError(t, runChangelogCommand(flags, nil, config))
+
+	content, err := os.ReadFile(changelogFile)
+	require.NoError(t, err)
+	assert.Equal(t, changelogHeader+"\n## [1.4.0] - 2025-01-03\n\n"+
+		"### Added\n\n- Sign release artifacts ([EV-10](https://example.atlassian.net/browse/EV-10))\n\n"+
+		"### Fixed\n\n- Fix report encoding (EV-2)\n\n"+
+		"## [1.3.0] - 2024-12-01\n\n### Added\n\n- Merge command\n", string(content))
+
+	// Running again for the same version changes nothing, even on a later day
+	config.Clock = func() time.Time { return time.Date(2025, 1, 4, 9, 0, 0, 0, time.UTC) }
+	require.NoError(t, runChangelogCommand(flags, nil, config))
+	again, err := os.ReadFile(changelogFile)
+	require.NoError(t, err)
+	assert.Equal(t, string(content), string(again))
+
+	t.Run("Missing changelog is created", func(t *testing.T) {
+		newFile := filepath.Join(dir, "docs", "CHANGELOG.md")
+		flags := &FlagConfig{InputFile: inputFile, ChangelogFile: newFile, Version: "1.0.0", ExcludeLabels: "none"}
+		require.NoError(t, runChangelogCommand(flags, nil, config))
+		content, err := os.ReadFile(newFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), changelogHeader+"\n## [1.0.0] - 2025-01-04\n")
+		assert.Contains(t, string(content), "- Bump linters (EV-3)")
+	})
+}
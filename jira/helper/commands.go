@@ -0,0 +1,481 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// HelpCommand prints the overview or the help of a single command
+const HelpCommand = "help"
+
+// GateFailureExitCode is the exit code of the gate command when a policy rule fails
+const GateFailureExitCode = 1
+
+// Command is an explicit CLI subcommand with its own flags and help
+type Command struct {
+	Name    string
+	Usage   string
+	Summary string
+	// Offline commands never contact JIRA, so credentials are not required
+	Offline bool
+	// NoConfig commands do not read the config file, so they take no --config/--profile flags
+	NoConfig bool
+	// Flags registers the command's flags on its flag set
+	Flags func(fs *flag.FlagSet, flags *FlagConfig)
+	Run   func(flags *FlagConfig, args []string, config *AppConfig) error
+}
+
+// commands lists the subcommands in the order they are shown in the help
+var commands = []*Command{
+	{
+		Name:    "fetch",
+		Usage:   "fetch [flags] (<jira_id>... | --commit <commit>)",
+		Summary: "Fetch JIRA ticket details and write the evidence JSON",
+		Flags: func(fs *flag.FlagSet, flags *FlagConfig) {
+			fs.StringVar(&flags.Commit, "commit", "", "Extract JIRA IDs from this commit instead of taking them as arguments")
+			registerGitFlags(fs, flags)
+			fs.StringVar(&flags.OutputFile, "o", "", "Output file for JIRA data (default: transformed_jira_data.json)")
+			registerFetchFlags(fs, flags)
+		},
+		Run: runFetchCommand,
+	},
+	{
+		Name:    "update",
+		Usage:   "update [flags] [<jira_id>... | --commit <commit>]",
+		Summary: "Update a previous evidence JSON, fetching only new tickets and tickets changed in JIRA",
+		Flags: func(fs *flag.FlagSet, flags *FlagConfig) {
+			fs.StringVar(&flags.InputFile, "i", "", "Previous evidence JSON (default: $OUTPUT_FILE, the profile's output_file or transformed_jira_data.json)")
+			fs.StringVar(&flags.Commit, "commit", "", "Extract the current JIRA IDs from this commit instead of taking them as arguments")
+			registerGitFlags(fs, flags)
+			fs.StringVar(&flags.OutputFile, "o", "", "Output file for the updated JIRA data (default: transformed_jira_data.json)")
+			fs.BoolVar(&flags.Prune, "prune", false, "Remove previous tickets that are not among the current JIRA IDs")
+			registerFetchFlags(fs, flags)
+		},
+		Run: runUpdateCommand,
+	},
+	{
+		Name:    "extract",
+		Usage:   "extract [flags] <commit>",
+		Summary: "Print the JIRA IDs referenced by git commits without contacting JIRA",
+		Offline: true,
+		Flags:   registerGitFlags,
+		Run:     runExtractCommand,
+	},
+	{
+		Name:    "report",
+		Usage:   "report [flags]",
+		Summary: "Render a markdown or HTML report from an evidence JSON file",
+		Offline: true,
+		Flags: func(fs *flag.FlagSet, flags *FlagConfig) {
+			fs.StringVar(&flags.InputFile, "i", "", "Input evidence JSON (default: $OUTPUT_FILE, the profile's output_file or transformed_jira_data.json)")
+			fs.StringVar(&flags.MarkdownOutput, "o", "", "Output file for the report (default: transformed_jira_data.md, or .html for --format html)")
+			fs.StringVar(&flags.Format, "format", "", "Report format: markdown (default), or html for a self-contained page")
+			fs.StringVar(&flags.Template, "template", "", "Go text/template file for a markdown report (default: the built-in layout)")
+			registerSortFlags(fs, flags)
+		},
+		Run: func(flags *FlagConfig, args []string, config *AppConfig) error {
+			return runMarkdownMode(flags, config)
+		},
+	},
+	{
+		Name:    "gate",
+		Usage:   "gate [flags]",
+		Summary: "Fail when tickets in an evidence JSON file violate the release policy",
+		Offline: true,
+		Flags: func(fs *flag.FlagSet, flags *FlagConfig) {
+			fs.StringVar(&flags.InputFile, "i", "", "Input evidence JSON (default: $OUTPUT_FILE, the profile's output_file or transformed_jira_data.json)")
+			fs.StringVar(&flags.AllowedStatuses, "allowed-statuses", "",
+				"Comma-separated statuses tickets must be in (default: the done statuses), or 'none' to allow any")
+			fs.StringVar(&flags.DoneStatuses, "done-statuses", "", "Comma-separated statuses that count as done")
+			fs.BoolVar(&flags.AllowErrors, "allow-errors", false, "Let tickets that could not be fetched pass")
+			fs.BoolVar(&flags.RequireTasks, "require-tasks", false, "Fail when the evidence references no tickets")
+			fs.BoolVar(&flags.SeparationOfDuties, "separation-of-duties", false,
+				"Fail tickets that their own assignee moved into their current status")
+			fs.StringVar(&flags.Commit, "commit", "", "Also check that this commit (or with --range, every commit up to HEAD) references a JIRA ticket")
+			registerGitFlags(fs, flags)
+			fs.StringVar(&flags.JUnitOutput, "junit", "", "Also write the results as JUnit XML to this file, one test case per ticket")
+			fs.StringVar(&flags.SARIFOutput, "sarif", "", "Also write the violations as SARIF to this file, for code scanning")
+		},
+		Run: runGateCommand,
+	},
+	{
+		Name:    "diff",
+		Usage:   "diff [flags] <from_json> <to_json>",
+		Summary: "Show how ticket state changed between two evidence JSON files, e.g. QA and PROD",
+		Offline: true,
+		Flags: func(fs *flag.FlagSet, flags *FlagConfig) {
+			fs.StringVar(&flags.Format, "format", "", "Output format: markdown or json (default: markdown)")
+			fs.StringVar(&flags.OutputFile, "o", "", "Output file for the diff (default: standard output)")
+			fs.StringVar(&flags.DoneStatuses, "done-statuses", "", "Comma-separated statuses that count as done, for regressions")
+			fs.StringVar(&flags.FailOn, "fail-on", "",
+				"Exit with code 1 on these differences: added, removed, changed, regressed or any (default: never fail)")
+		},
+		Run: runDiffCommand,
+	},
+	{
+		Name:    "merge",
+		Usage:   "merge [flags] [<component>=]<input_json>...",
+		Summary: "Merge the evidence JSON of several builds into one application-level predicate",
+		Offline: true,
+		Flags: func(fs *flag.FlagSet, flags *FlagConfig) {
+			fs.StringVar(&flags.OutputFile, "o", "", "Output file for the merged JSON (default: transformed_jira_data.json)")
+			registerSortFlags(fs, flags)
+		},
+		Run: runMergeCommand,
+	},
+	{
+		Name:    "export",
+		Usage:   "export [flags]",
+		Summary: "Export the tickets and transitions of an evidence JSON file as CSV or XLSX",
+		Offline: true,
+		Flags: func(fs *flag.FlagSet, flags *FlagConfig) {
+			fs.StringVar(&flags.InputFile, "i", "", "Input evidence JSON (default: $OUTPUT_FILE, the profile's output_file or transformed_jira_data.json)")
+			fs.StringVar(&flags.ExportOutput, "o", "",
+				"Output file (default: the fetch output file as .csv or .xlsx); csv writes <name>_tasks.csv and <name>_transitions.csv")
+			fs.StringVar(&flags.Format, "format", "", "Output format: csv or xlsx (default: csv)")
+			registerSortFlags(fs, flags)
+		},
+		Run: runExportCommand,
+	},
+	{
+		Name:    "release-notes",
+		Usage:   "release-notes [flags]",
+		Summary: "Render release notes from an evidence JSON file, grouped by issue type or component",
+		Offline: true,
+		Flags: func(fs *flag.FlagSet, flags *FlagConfig) {
+			fs.StringVar(&flags.InputFile, "i", "", "Input evidence JSON (default: $OUTPUT_FILE, the profile's output_file or transformed_jira_data.json)")
+			fs.StringVar(&flags.ReleaseNotesOutput, "o", "", "Output file for the release notes (default: standard output)")
+			fs.StringVar(&flags.Format, "format", "", "Output format: markdown or text (default: markdown)")
+			fs.StringVar(&flags.Version, "version", "", "Version in the heading (default: the latest git tag, or "+UnreleasedVersion+")")
+			fs.StringVar(&flags.GroupBy, "group-by", "",
+				"Comma-separated grouping levels: type, component or project, at most two, or 'none' (default: type)")
+			fs.StringVar(&flags.ExcludeLabels, "exclude-labels", "",
+				"Comma-separated labels that keep tickets out of the release notes, or 'none' (default: "+DefaultExcludeLabels+")")
+		},
+		Run: runReleaseNotesCommand,
+	},
+	{
+		Name:    "changelog",
+		Usage:   "changelog [flags]",
+		Summary: "Add a version section built from an evidence JSON file to a Keep a Changelog CHANGELOG.md",
+		Offline: true,
+		Flags: func(fs *flag.FlagSet, flags *FlagConfig) {
+			fs.StringVar(&flags.InputFile, "i", "", "Input evidence JSON (default: $OUTPUT_FILE, the profile's output_file or transformed_jira_data.json)")
+			fs.StringVar(&flags.ChangelogFile, "o", "", "Changelog to update, created when missing (default: "+DefaultChangelogFile+")")
+			fs.StringVar(&flags.Version, "version", "", "Version of the new section (default: the latest git tag, or "+UnreleasedVersion+")")
+			fs.StringVar(&flags.ExcludeLabels, "exclude-labels", "",
+				"Comma-separated labels that keep tickets out of the changelog, or 'none' (default: "+DefaultExcludeLabels+")")
+		},
+		Run: runChangelogCommand,
+	},
+	{
+		Name:     SchemaCommand,
+		Usage:    SchemaCommand,
+		Summary:  "Print the predicate JSON Schema",
+		Offline:  true,
+		NoConfig: true,
+		Run: func(flags *FlagConfig, args []string, config *AppConfig) error {
+			return runSchemaMode()
+		},
+	},
+	{
+		Name:    MigrateCommand,
+		Usage:   MigrateCommand + " [flags] <input_json>",
+		Summary: "Upgrade historical evidence to the current schema version",
+		Offline: true,
+		Flags: func(fs *flag.FlagSet, flags *FlagConfig) {
+			fs.StringVar(&flags.OutputFile, "o", "", "Output file for the migrated JSON (default: transformed_jira_data.json)")
+		},
+		Run: func(flags *FlagConfig, args []string, config *AppConfig) error {
+			return runMigrateMode(args, config)
+		},
+	},
+	{
+		Name:     "serve-fake",
+		Usage:    "serve-fake [flags]",
+		Summary:  "Serve a fake JIRA REST API from fixture files for offline tests and demos",
+		Offline:  true,
+		NoConfig: true,
+		Flags: func(fs *flag.FlagSet, flags *FlagConfig) {
+			fs.StringVar(&flags.Addr, "addr", "", "Address to listen on (default: "+DefaultFakeJiraAddr+")")
+			fs.StringVar(&flags.Fixtures, "fixtures", "", "Fixture directory (default: the built-in fixtures)")
+			fs.StringVar(&flags.FakeUsername, "username", "", "Only accept this basic auth username (default: accept any)")
+			fs.StringVar(&flags.FakeToken, "token", "", "Only accept this API token (default: accept any)")
+			fs.IntVar(&flags.PageSize, "page-size", 0, fmt.Sprintf("Maximum page size of paginated endpoints (default: %d)", DefaultFakeJiraPageSize))
+		},
+		Run: func(flags *FlagConfig, args []string, config *AppConfig) error {
+			return runServeFakeMode(flags)
+		},
+	},
+}
+
+// registerCommandFlags registers the command's own flags and, unless it ignores the config file, the config flags
+func registerCommandFlags(cmd *Command, fs *flag.FlagSet, flags *FlagConfig) {
+	if cmd.Flags != nil {
+		cmd.Flags(fs, flags)
+	}
+	if !cmd.NoConfig {
+		registerConfigFlags(fs, flags)
+	}
+}
+
+// registerGitFlags registers the flags of commands that read JIRA IDs from git history
+func registerGitFlags(fs *flag.FlagSet, flags *FlagConfig) {
+	fs.StringVar(&flags.JIRAIDRegex, "r", "", "JIRA ID regex pattern (default: '[A-Z]+-[0-9]+')")
+	fs.BoolVar(&flags.CommitRange, "range", false, "Process commits from the specified commit to HEAD (instead of single commit)")
+}
+
+// registerFetchFlags registers the flags that shape fetched evidence
+func registerFetchFlags(fs *flag.FlagSet, flags *FlagConfig) {
+	fs.StringVar(&flags.TrackFields, "track-fields", "", "Comma-separated changelog fields to record besides status, or 'none'")
+	fs.StringVar(&flags.DoneStatuses, "done-statuses", "", "Comma-separated statuses that count as done for lead/cycle time")
+	fs.StringVar(&flags.InProgressStatuses, "in-progress-statuses", "", "Comma-separated statuses that start the cycle time")
+	fs.StringVar(&flags.AsOf, "as-of", "", "Report ticket state as of this timestamp (RFC 3339)")
+	fs.StringVar(&flags.ErrorExitCodes, "error-exit-codes", "", "Exit codes per error class, e.g. 'unauthorized=3,not_found=0,*=1'")
+	fs.StringVar(&flags.Record, "record", "", "Record the JIRA API exchanges, scrubbed of credentials, to this cassette file")
+	fs.StringVar(&flags.Replay, "replay", "", "Answer JIRA API requests from this cassette file instead of JIRA (no credentials needed)")
+	fs.StringVar(&flags.CacheDir, "cache-dir", "", "Cache fetched issues in this directory (default: $JIRA_CACHE_DIR, no cache when unset)")
+	fs.StringVar(&flags.CacheTTL, "cache-ttl", "", "Use cached issues without revalidation for this long (default: 15m)")
+	fs.BoolVar(&flags.CacheOnly, "offline", false, "Serve issues strictly from the cache, without contacting JIRA")
+	registerSortFlags(fs, flags)
+}
+
+// registerSortFlags registers the flags that order the tickets of written evidence and reports
+func registerSortFlags(fs *flag.FlagSet, flags *FlagConfig) {
+	fs.StringVar(&flags.SortTasks, "sort-tasks", "",
+		"Sort tickets by key, created, updated or status, or 'none' to keep their order (default: none, key with $SOURCE_DATE_EPOCH)")
+}
+
+// findCommand returns the subcommand with the given name, or nil
+func findCommand(name string) *Command {
+	for _, cmd := range commands {
+		if cmd.Name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// dispatchCommand runs the subcommand named by argv[0].
+// handled is false when argv does not start with a subcommand, so the caller can fall back to the legacy flags.
+func dispatchCommand(argv []string) (handled bool, err error) {
+	if len(argv) == 0 {
+		return false, nil
+	}
+	if argv[0] == HelpCommand {
+		return true, runHelpCommand(argv[1:], os.Stdout)
+	}
+	cmd := findCommand(argv[0])
+	if cmd == nil {
+		return false, nil
+	}
+	return true, runCommand(cmd, argv[1:])
+}
+
+// runCommand parses the command's flags, loads the configuration and runs it
+func runCommand(cmd *Command, argv []string) error {
+	flags, args, err := parseCommandFlags(cmd, argv, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if cmd.NoConfig {
+		return cmd.Run(flags, args, &AppConfig{})
+	}
+
+	config, err := LoadConfig(flags, args)
+	if err != nil {
+		printCommandUsage(cmd, os.Stderr)
+		return fmt.Errorf("error loading configuration: %w", err)
+	}
+
+	return cmd.Run(flags, args, config)
+}
+
+// parseCommandFlags parses argv with the command's own flag set
+func parseCommandFlags(cmd *Command, argv []string, output io.Writer) (*FlagConfig, []string, error) {
+	flags := &FlagConfig{Offline: cmd.Offline}
+	fs := newCommandFlagSet(cmd, flags, output)
+	if err := fs.Parse(argv); err != nil {
+		return nil, nil, err
+	}
+	return flags, fs.Args(), nil
+}
+
+func newCommandFlagSet(cmd *Command, flags *FlagConfig, output io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	fs.SetOutput(output)
+	registerCommandFlags(cmd, fs, flags)
+	fs.Usage = func() {
+		printCommandUsage(cmd, fs.Output())
+	}
+	return fs
+}
+
+// printCommandUsage prints the usage line, summary and flags of a command
+func printCommandUsage(cmd *Command, output io.Writer) {
+	fmt.Fprintf(output, "Usage: ./main %s\n", cmd.Usage)
+	fmt.Fprintln(output, "")
+	fmt.Fprintln(output, cmd.Summary)
+
+	// Print the flag defaults from a throwaway flag set so usage never touches parsed state
+	fs := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	registerCommandFlags(cmd, fs, &FlagConfig{})
+	hasFlags := false
+	fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		fmt.Fprintln(output, "")
+		fmt.Fprintln(output, "Flags:")
+		fs.SetOutput(output)
+		fs.PrintDefaults()
+	}
+}
+
+// runHelpCommand prints the help of the named command, or the overview without arguments
+func runHelpCommand(args []string, output io.Writer) error {
+	if len(args) == 0 {
+		DisplayUsage()
+		return nil
+	}
+	cmd := findCommand(args[0])
+	if cmd == nil {
+		return fmt.Errorf("unknown command '%s'", args[0])
+	}
+	printCommandUsage(cmd, output)
+	return nil
+}
+
+// runFetchCommand fetches the given tickets, or the tickets referenced by --commit
+func runFetchCommand(flags *FlagConfig, args []string, config *AppConfig) error {
+	if flags.Commit == "" {
+		if len(args) == 0 {
+			return fmt.Errorf("missing JIRA IDs (or --commit)")
+		}
+		if err := checkJiraIDArgs(args, config.JIRAIDRegex); err != nil {
+			return err
+		}
+		config.JIRAIDs = args
+		return processDirectJiraIDs(config)
+	}
+
+	if len(args) > 0 {
+		return fmt.Errorf("unexpected arguments with --commit: %s", strings.Join(args, " "))
+	}
+	config.StartCommit = flags.Commit
+
+	git := NewGitService()
+	if err := git.CheckRepository(); err != nil {
+		return err
+	}
+	return runFullMode(config)
+}
+
+// checkJiraIDArgs rejects arguments that are not JIRA IDs as a whole, so they never reach JIRA URLs or cache paths
+func checkJiraIDArgs(args []string, pattern string) error {
+	pattern = getOrDefault(pattern, DefaultJIRAIDRegex)
+	regex, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return &ValidationError{Field: "JIRA_ID_REGEX", Value: pattern, Err: err}
+	}
+	for _, arg := range args {
+		if !regex.MatchString(arg) {
+			return &ValidationError{Field: "JIRA ID", Value: arg, Err: fmt.Errorf("does not match the JIRA ID regex %s", pattern)}
+		}
+	}
+	return nil
+}
+
+// runExtractCommand prints the JIRA IDs referenced by the given commit (or range)
+func runExtractCommand(flags *FlagConfig, args []string, config *AppConfig) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected exactly one commit, got %d arguments", len(args))
+	}
+	config.StartCommit = args[0]
+	config.ExtractOnly = true
+
+	git := NewGitService()
+	if err := git.CheckRepository(); err != nil {
+		return err
+	}
+	return runExtractOnlyMode(config)
+}
+
+// runGateCommand evaluates an evidence file against the release policy
+func runGateCommand(flags *FlagConfig, args []string, config *AppConfig) error {
+	inputFile := resolveInputFile(flags, config)
+
+	fmt.Println("=== JIRA Release Gate ===")
+	fmt.Printf("Input JSON file: %s\n", inputFile)
+	fmt.Println("")
+
+	response, _, err := loadTransitionResponse(inputFile)
+	if err != nil {
+		return err
+	}
+
+	policy := GatePolicy{
+		AllowedStatuses:    config.DoneStatuses,
+		AllowErrors:        flags.AllowErrors,
+		RequireTasks:       flags.RequireTasks,
+		SeparationOfDuties: flags.SeparationOfDuties,
+	}
+	if flags.AllowedStatuses != "" {
+		policy.AllowedStatuses = parseFieldList(flags.AllowedStatuses)
+	}
+
+	report := evaluatePolicy(response, policy)
+	repositoryURI := ""
+	if flags.Commit != "" {
+		git := NewGitService()
+		if err := git.CheckRepository(); err != nil {
+			return err
+		}
+		commits, err := git.ListCommits(flags.Commit, config.JIRAIDRegex, config.SingleCommit)
+		if err != nil {
+			return fmt.Errorf("error listing commits: %v", err)
+		}
+		checkCommits(report, commits)
+		repositoryURI = git.RemoteURL()
+	}
+	printGateReport(report)
+
+	// Reports are written before failing, so CI can publish them either way
+	if flags.JUnitOutput != "" {
+		if err := writeJUnitReport(report, response, inputFile, flags.JUnitOutput); err != nil {
+			return err
+		}
+		fmt.Printf("JUnit report saved to: %s\n", flags.JUnitOutput)
+	}
+	if flags.SARIFOutput != "" {
+		// Outside a repository the evidence file is located by an absolute URI instead
+		repositoryRoot, _ := NewGitService().TopLevel()
+		if err := writeSARIFReport(report, inputFile, repositoryRoot, repositoryURI, flags.SARIFOutput); err != nil {
+			return err
+		}
+		fmt.Printf("SARIF report saved to: %s\n", flags.SARIFOutput)
+	}
+
+	if !report.Passed() {
+		return &ExitCodeError{Code: GateFailureExitCode, Err: fmt.Errorf("release gate failed")}
+	}
+	return nil
+}
+
+// resolveInputFile returns the evidence file read by report, gate and the other commands taking -i
+func resolveInputFile(flags *FlagConfig, config *AppConfig) string {
+	return getOrDefault(flags.InputFile, flags.OutputFile, config.evidenceFile())
+}
+
+// evidenceFile returns the file fetch writes without -o; configs not built by LoadConfig fall back to
+// OUTPUT_FILE and the default
+func (c *AppConfig) evidenceFile() string {
+	return getOrDefault(c.EvidenceFile, os.Getenv("OUTPUT_FILE"), DefaultOutputFile)
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindCommand(t *testing.T) {
+	for _, name := range []string{"fetch", "extract", "report", "gate", SchemaCommand, MigrateCommand} {
+		cmd := findCommand(name)
+		require.NotNil(t, cmd, "command %s not registered", name)
+		assert.Equal(t, name, cmd.Name)
+		assert.NotEmpty(t, cmd.Summary)
+	}
+
+	assert.Nil(t, findCommand("EV-123"))
+	assert.Nil(t, findCommand("deadbeef"))
+	assert.Nil(t, findCommand("--markdown"))
+}
+
+func TestDispatchCommandFallsBackForLegacyInvocations(t *testing.T) {
+	tests := [][]string{
+		{},
+		{"EV-123", "EV-456"},
+		{"deadbeef"},
+		{"--extract-only", "abc123"},
+		{"--markdown"},
+		{"-o", "out.json", "migrate", "legacy.json"},
+	}
+
+	for _, argv := range tests {
+		handled, err := dispatchCommand(argv)
+		assert.False(t, handled, "argv %v", argv)
+		assert.NoError(t, err)
+	}
+}
+
+func TestParseCommandFlags(t *testing.T) {
+	tests := []struct {
+		name          string
+		command       string
+		argv          []string
+		expectedFlags *FlagConfig
+		expectedArgs  []string
+		expectError   bool
+	}{
+		{
+			name:    "Fetch tickets",
+			command: "fetch",
+			argv:    []string{"-o", "out.json", "--as-of", "2025-01-31", "EV-1", "EV-2"},
+			expectedFlags: &FlagConfig{
+				OutputFile: "out.json",
+				AsOf:       "2025-01-31",
+			},
+			expectedArgs: []string{"EV-1", "EV-2"},
+		},
+		{
+			name:    "Fetch from commit range",
+			command: "fetch",
+			argv:    []string{"--range", "-r", "EV-[0-9]+", "--commit", "deadbeef"},
+			expectedFlags: &FlagConfig{
+				Commit:      "deadbeef",
+				CommitRange: true,
+				JIRAIDRegex: "EV-[0-9]+",
+			},
+			expectedArgs: []string{},
+		},
+		{
+			name:          "Extract is offline",
+			command:       "extract",
+			argv:          []string{"deadbeef"},
+			expectedFlags: &FlagConfig{Offline: true},
+			expectedArgs:  []string{"deadbeef"},
+		},
+		{
+			name:    "Report input and output",
+			command: "report",
+			argv:    []string{"-i", "in.json", "-o", "out.md"},
+			expectedFlags: &FlagConfig{
+				Offline:        true,
+				InputFile:      "in.json",
+				MarkdownOutput: "out.md",
+			},
+			expectedArgs: []string{},
+		},
+		{
+			name:    "Gate policy",
+			command: "gate",
+			argv:    []string{"--allowed-statuses", "Done", "--allow-errors", "--require-tasks"},
+			expectedFlags: &FlagConfig{
+				Offline:         true,
+				AllowedStatuses: "Done",
+				AllowErrors:     true,
+				RequireTasks:    true,
+			},
+			expectedArgs: []string{},
+		},
+		{
+			name:        "Flags of other commands are rejected",
+			command:     "report",
+			argv:        []string{"--commit", "abc123"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			flags, args, err := parseCommandFlags(findCommand(tt.command), tt.argv, &output)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, output.String(), "Usage: ./main "+tt.command)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedFlags, flags)
+			assert.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
+
+func TestParseCommandFlagsHelp(t *testing.T) {
+	var output bytes.Buffer
+	_, _, err := parseCommandFlags(findCommand("gate"), []string{"-h"}, &output)
+
+	assert.True(t, errors.Is(err, flag.ErrHelp))
+	assert.Contains(t, output.String(), "Usage: ./main gate [flags]")
+	assert.Contains(t, output.String(), "-allowed-statuses")
+}
+
+func TestRunHelpCommand(t *testing.T) {
+	var output bytes.Buffer
+	require.NoError(t, runHelpCommand([]string{"fetch"}, &output))
+	assert.Contains(t, output.String(), "Usage: ./main fetch")
+	assert.Contains(t, output.String(), "-commit")
+	assert.Contains(t, output.String(), "-error-exit-codes")
+
+	output.Reset()
+	require.NoError(t, runHelpCommand([]string{SchemaCommand}, &output))
+	assert.NotContains(t, output.String(), "Flags:")
+
+	err := runHelpCommand([]string{"unknown"}, &output)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown command 'unknown'")
+}
+
+func TestOfflineCommandsSkipJIRACredentials(t *testing.T) {
+	t.Setenv("JIRA_API_TOKEN", "")
+	t.Setenv("JIRA_URL", "")
+	t.Setenv("JIRA_USERNAME", "")
+
+	for _, cmd := range commands {
+		flags, args, err := parseCommandFlags(cmd, []string{}, &bytes.Buffer{})
+		require.NoError(t, err)
+
+		_, err = LoadConfig(flags, args)
+		if cmd.Offline {
+			assert.NoError(t, err, "command %s", cmd.Name)
+		} else {
+			assert.Error(t, err, "command %s", cmd.Name)
+		}
+	}
+}
+
+func TestRunFetchCommandArguments(t *testing.T) {
+	err := runFetchCommand(&FlagConfig{}, []string{}, &AppConfig{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing JIRA IDs")
+
+	err = runFetchCommand(&FlagConfig{Commit: "abc123"}, []string{"EV-1"}, &AppConfig{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected arguments with --commit")
+}
+
+func TestCheckJiraIDArgs(t *testing.T) {
+	assert.NoError(t, checkJiraIDArgs([]string{"EV-1", "OPS-22"}, ""))
+	assert.NoError(t, checkJiraIDArgs([]string{"EV-1"}, "EV-[0-9]+"))
+
+	for _, arg := range []string{"../../x", "EV-1/../../x", "ev-1", "EV-1 ", "OPS-1"} {
+		err := checkJiraIDArgs([]string{"EV-1", arg}, "EV-[0-9]+")
+		var validationErr *ValidationError
+		require.True(t, errors.As(err, &validationErr), arg)
+		assert.Equal(t, arg, validationErr.Value)
+	}
+
+	err := runFetchCommand(&FlagConfig{}, []string{"../../x"}, &AppConfig{})
+	assert.ErrorContains(t, err, "does not match the JIRA ID regex")
+}
+
+func TestRunExtractCommandArguments(t *testing.T) {
+	err := runExtractCommand(&FlagConfig{}, []string{}, &AppConfig{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected exactly one commit")
+
+	err = runExtractCommand(&FlagConfig{}, []string{"a", "b"}, &AppConfig{})
+	require.Error(t, err)
+}
+
+func TestRunGateCommand(t *testing.T) {
+	tempDir := t.TempDir()
+	writeEvidence := func(name string, tasks []JiraTransitionResult) string {
+		path := filepath.Join(tempDir, name)
+		data, err := json.Marshal(TransitionCheckResponse{SchemaVersion: CurrentSchemaVersion, Tasks: tasks})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, data, 0644))
+		return path
+	}
+
+	done := writeEvidence("done.json", []JiraTransitionResult{
+		{Key: "EV-1", Status: "Done", Transitions: []Transition{}},
+	})
+	mixed := writeEvidence("mixed.json", []JiraTransitionResult{
+		{Key: "EV-1", Status: "Done", Transitions: []Transition{}},
+		{Key: "EV-2", Status: "In Review", Transitions: []Transition{}},
+	})
+	config := &AppConfig{DoneStatuses: parseFieldList(DefaultDoneStatuses)}
+
+	t.Run("All tickets done", func(t *testing.T) {
+		assert.NoError(t, runGateCommand(&FlagConfig{InputFile: done}, nil, config))
+	})
+
+	t.Run("Ticket not done fails with exit code", func(t *testing.T) {
+		err := runGateCommand(&FlagConfig{InputFile: mixed}, nil, config)
+		require.Error(t, err)
+		var exitErr *ExitCodeError
+		require.True(t, errors.As(err, &exitErr))
+		assert.Equal(t, GateFailureExitCode, exitErr.Code)
+	})
+
+	t.Run("JUnit report is written when the gate fails", func(t *testing.T) {
+		junitFile := filepath.Join(tempDir, "gate.xml")
+		err := runGateCommand(&FlagConfig{InputFile: mixed, JUnitOutput: junitFile}, nil, config)
+		require.Error(t, err)
+
+		data, err := os.ReadFile(junitFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `<testsuites name="jira-release-gate" tests="2" failures="1" skipped="0">`)
+		assert.Contains(t, string(data), `type="jira/status-not-allowed"`)
+	})
+
+	t.Run("SARIF report is written when the gate fails", func(t *testing.T) {
+		sarifFile := filepath.Join(tempDir, "gate.sarif")
+		err := runGateCommand(&FlagConfig{InputFile: mixed, SARIFOutput: sarifFile}, nil, config)
+		require.Error(t, err)
+
+		data, err := os.ReadFile(sarifFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"ruleId": "jira/status-not-allowed"`)
+		assert.Contains(t, string(data), `"jiraKey": "EV-2"`)
+	})
+
+	t.Run("Custom allowed statuses", func(t *testing.T) {
+		flags := &FlagConfig{InputFile: mixed, AllowedStatuses: "Done,In Review"}
+		assert.NoError(t, runGateCommand(flags, nil, config))
+	})
+
+	t.Run("Missing input file", func(t *testing.T) {
+		err := runGateCommand(&FlagConfig{InputFile: filepath.Join(tempDir, "missing.json")}, nil, config)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "error reading JSON file")
+	})
+}
+
+func TestResolveInputFile(t *testing.T) {
+	t.Setenv("OUTPUT_FILE", "")
+	assert.Equal(t, DefaultOutputFile, resolveInputFile(&FlagConfig{}, &AppConfig{}))
+	assert.Equal(t, "legacy.json", resolveInputFile(&FlagConfig{OutputFile: "legacy.json"}, &AppConfig{}))
+	assert.Equal(t, "in.json", resolveInputFile(&FlagConfig{InputFile: "in.json", OutputFile: "legacy.json"}, &AppConfig{}))
+	assert.Equal(t, "profile.json", resolveInputFile(&FlagConfig{}, &AppConfig{EvidenceFile: "profile.json"}))
+
+	t.Setenv("OUTPUT_FILE", "env.json")
+	assert.Equal(t, "env.json", resolveInputFile(&FlagConfig{}, &AppConfig{}))
+}
+
+func TestProfileOutputFileFromFetchToReport(t *testing.T) {
+	clearConfigEnv(t)
+	_, server := newFakeJiraServer(t, "ci@example.com", "token")
+	dir := t.TempDir()
+	evidenceFile := filepath.Join(dir, "evidence", "app.json")
+	configFile := writeConfigFile(t, fmt.Sprintf("profiles:\n  ci:\n    url: %s\n    username: ci@example.com\n    output_file: %s\n",
+		server.URL, evidenceFile))
+	t.Setenv("JIRA_HELPER_CONFIG", configFile)
+	t.Setenv("JIRA_HELPER_PROFILE", "ci")
+	t.Setenv("JIRA_API_TOKEN", "token")
+
+	require.NoError(t, runCommand(findCommand("fetch"), []string{"EV-1"}))
+	require.FileExists(t, evidenceFile)
+
+	reportFile := filepath.Join(dir, "report.md")
+	require.NoError(t, runCommand(findCommand("report"), []string{"-o", reportFile}))
+	report, err := os.ReadFile(reportFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(report), "EV-1")
+
+	// update reads the profile's file as the previous evidence too
+	updatedFile := filepath.Join(dir, "updated.json")
+	require.NoError(t, runCommand(findCommand("update"), []string{"-o", updatedFile, "EV-1", "EV-2"}))
+	updated, _, err := loadTransitionResponse(updatedFile)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"EV-1"}, updated.Update.Unchanged)
+	assert.Equal(t, []string{"EV-2"}, updated.Update.Added)
+}
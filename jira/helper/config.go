@@ -3,25 +3,67 @@ package main
 import (
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 )
 
 // Constants for default values
 const (
 	DefaultJIRAIDRegex = "[A-Z]+-[0-9]+"
 	DefaultOutputFile  = "transformed_jira_data.json"
+	// DefaultTrackedFields lists the non-status changelog fields recorded per task
+	DefaultTrackedFields = "assignee,priority,Fix Version,Sprint"
 )
 
 // AppConfig holds all configuration for the application
 type AppConfig struct {
+	// Profile is the config file profile in use, empty when none is selected
+	Profile string
+
 	// JIRA Configuration
 	JIRAToken    string
 	JIRAURL      string
 	JIRAUsername string
 	JIRAIDRegex  string
 
+	// TrackedFields lists the changelog fields (besides status) recorded as field changes
+	TrackedFields []string
+
+	// Metrics Configuration
+	DoneStatuses       []string
+	InProgressStatuses []string
+
+	// AsOf reconstructs ticket state at this instant when set
+	AsOf time.Time
+
+	// ErrorExitCodes maps error classes (or "*") to the exit code used when they occur
+	ErrorExitCodes map[string]int
+
+	// HTTPClient sends JIRA requests when set (tests, proxies); authentication is added on top of it
+	HTTPClient *http.Client
+
+	// RecordFile receives a scrubbed cassette of the JIRA API exchanges; ReplayFile answers them from one
+	RecordFile string
+	ReplayFile string
+
+	// CacheDir enables the on-disk issue cache; entries younger than CacheTTL skip JIRA entirely.
+	// CacheOnly (--offline) serves strictly from the cache.
+	CacheDir  string
+	CacheTTL  time.Duration
+	CacheOnly bool
+
 	// Output Configuration
 	OutputFile string
+	// EvidenceFile is OutputFile without the -o override (OUTPUT_FILE, the profile's output_file or the default),
+	// read by commands that take an evidence file when no -i is given
+	EvidenceFile string
+	// TaskOrder sorts the tickets of written evidence and reports (--sort-tasks)
+	TaskOrder string
+
+	// Clock returns the current time when set; SOURCE_DATE_EPOCH pins it for reproducible output
+	Clock func() time.Time
 
 	// Runtime Configuration
 	ExtractOnly    bool
@@ -33,15 +75,58 @@ type AppConfig struct {
 
 // FlagConfig holds command line flags
 type FlagConfig struct {
-	JIRAIDRegex      string
-	OutputFile       string
-	ExtractOnly      bool
-	ExtractFromGit   bool
-	CommitRange      bool
-	Help             bool
-	HelpLong         bool
-	GenerateMarkdown bool
-	MarkdownOutput   string
+	JIRAIDRegex        string
+	OutputFile         string
+	ExtractOnly        bool
+	ExtractFromGit     bool
+	CommitRange        bool
+	Help               bool
+	HelpLong           bool
+	GenerateMarkdown   bool
+	MarkdownOutput     string
+	TrackFields        string
+	DoneStatuses       string
+	InProgressStatuses string
+	AsOf               string
+	ErrorExitCodes     string
+	SortTasks          string
+
+	// Config file flags
+	ConfigFile string
+	Profile    string
+
+	// Subcommand flags
+	Commit             string
+	InputFile          string
+	AllowedStatuses    string
+	AllowErrors        bool
+	RequireTasks       bool
+	Offline            bool
+	Record             string
+	Replay             string
+	CacheDir           string
+	CacheTTL           string
+	CacheOnly          bool
+	Prune              bool
+	Format             string
+	FailOn             string
+	Template           string
+	ExportOutput       string
+	JUnitOutput        string
+	SARIFOutput        string
+	SeparationOfDuties bool
+	ReleaseNotesOutput string
+	GroupBy            string
+	ExcludeLabels      string
+	Version            string
+	ChangelogFile      string
+
+	// serve-fake flags
+	Addr         string
+	Fixtures     string
+	FakeUsername string
+	FakeToken    string
+	PageSize     int
 }
 
 // ParseFlags parses command line flags
@@ -56,26 +141,119 @@ func ParseFlags() (*FlagConfig, []string) {
 	flag.BoolVar(&flags.HelpLong, "help", false, "Display help message")
 	flag.BoolVar(&flags.GenerateMarkdown, "markdown", false, "Generate markdown from existing JSON file")
 	flag.StringVar(&flags.MarkdownOutput, "markdown-output", "", "Output file for markdown (default: transformed_jira_data.md)")
+	flag.StringVar(&flags.TrackFields, "track-fields", "", "Comma-separated changelog fields to record besides status, or 'none'")
+	flag.StringVar(&flags.DoneStatuses, "done-statuses", "", "Comma-separated statuses that count as done for lead/cycle time")
+	flag.StringVar(&flags.InProgressStatuses, "in-progress-statuses", "", "Comma-separated statuses that start the cycle time")
+	flag.StringVar(&flags.AsOf, "as-of", "", "Report ticket state as of this timestamp (RFC 3339)")
+	flag.StringVar(&flags.ErrorExitCodes, "error-exit-codes", "", "Exit codes per error class, e.g. 'unauthorized=3,not_found=0,*=1'")
+	registerConfigFlags(flag.CommandLine, flags)
 	flag.Parse()
 
 	return flags, flag.Args()
 }
 
-// LoadConfig loads configuration from flags and environment variables
+// registerConfigFlags registers the flags that select the config file and profile
+func registerConfigFlags(fs *flag.FlagSet, flags *FlagConfig) {
+	fs.StringVar(&flags.ConfigFile, "config", "", "Config file with named profiles (default: "+DefaultConfigFile+" if present)")
+	fs.StringVar(&flags.Profile, "profile", "", "Config file profile to use (default: the file's default_profile)")
+}
+
+// LoadConfig loads configuration with precedence flags > environment variables > config file profile > defaults
 func LoadConfig(flags *FlagConfig, args []string) (*AppConfig, error) {
+	profile, err := loadProfile(flags)
+	if err != nil {
+		return nil, err
+	}
+
+	evidenceFile := getOrDefault(os.Getenv("OUTPUT_FILE"), profile.OutputFile, DefaultOutputFile)
 	config := &AppConfig{
-		JIRAIDRegex:    getOrDefault(flags.JIRAIDRegex, os.Getenv("JIRA_ID_REGEX"), DefaultJIRAIDRegex),
-		OutputFile:     getOrDefault(flags.OutputFile, os.Getenv("OUTPUT_FILE"), DefaultOutputFile),
+		Profile:        profile.Name,
+		JIRAIDRegex:    getOrDefault(flags.JIRAIDRegex, os.Getenv("JIRA_ID_REGEX"), profile.JIRAIDRegex, DefaultJIRAIDRegex),
+		OutputFile:     getOrDefault(flags.OutputFile, evidenceFile),
+		EvidenceFile:   evidenceFile,
 		ExtractOnly:    flags.ExtractOnly,
 		ExtractFromGit: flags.ExtractFromGit,
 		SingleCommit:   !flags.CommitRange, // Default to single commit unless --range is specified
+		TrackedFields: parseFieldList(getOrDefault(flags.TrackFields, os.Getenv("JIRA_TRACKED_FIELDS"), profile.TrackedFields,
+			DefaultTrackedFields)),
+		DoneStatuses: parseFieldList(getOrDefault(flags.DoneStatuses, os.Getenv("JIRA_DONE_STATUSES"), profile.DoneStatuses,
+			DefaultDoneStatuses)),
+		InProgressStatuses: parseFieldList(getOrDefault(flags.InProgressStatuses, os.Getenv("JIRA_IN_PROGRESS_STATUSES"),
+			profile.InProgressStatuses, DefaultInProgressStatuses)),
+	}
+
+	if flags.AsOf != "" {
+		asOf, err := parseAsOf(flags.AsOf)
+		if err != nil {
+			return nil, &ValidationError{Field: "as-of", Value: flags.AsOf, Err: err}
+		}
+		config.AsOf = asOf
+	}
+
+	if config.Clock, err = loadClock(); err != nil {
+		return nil, err
 	}
 
-	// Load JIRA credentials only if not in extract-only mode or markdown mode
-	if !config.ExtractOnly && !config.ExtractFromGit && !flags.GenerateMarkdown {
-		config.JIRAToken = os.Getenv("JIRA_API_TOKEN")
-		config.JIRAURL = os.Getenv("JIRA_URL")
-		config.JIRAUsername = os.Getenv("JIRA_USERNAME")
+	// A pinned clock asks for reproducible output, so tickets are then sorted by key unless configured otherwise
+	defaultOrder := TaskOrderNone
+	if config.Clock != nil {
+		defaultOrder = TaskOrderKey
+	}
+	orderValue := getOrDefault(flags.SortTasks, os.Getenv("JIRA_SORT_TASKS"), profile.SortTasks, defaultOrder)
+	if config.TaskOrder, err = parseTaskOrder(orderValue); err != nil {
+		return nil, &ValidationError{Field: "sort-tasks", Value: orderValue, Err: err}
+	}
+
+	exitCodesValue := getOrDefault(flags.ErrorExitCodes, os.Getenv("JIRA_ERROR_EXIT_CODES"), profile.ErrorExitCodes)
+	exitCodes, err := parseErrorExitCodes(exitCodesValue)
+	if err != nil {
+		return nil, &ValidationError{Field: "error-exit-codes", Value: exitCodesValue, Err: err}
+	}
+	config.ErrorExitCodes = exitCodes
+
+	if flags.Record != "" && flags.Replay != "" {
+		return nil, &ValidationError{Field: "replay", Value: flags.Replay, Err: fmt.Errorf("cannot be combined with --record")}
+	}
+	config.RecordFile = flags.Record
+	config.ReplayFile = flags.Replay
+
+	config.CacheDir = getOrDefault(flags.CacheDir, os.Getenv("JIRA_CACHE_DIR"))
+	config.CacheOnly = flags.CacheOnly
+	if config.CacheDir != "" {
+		config.CacheTTL = DefaultCacheTTL
+	}
+	if ttl := getOrDefault(flags.CacheTTL, os.Getenv("JIRA_CACHE_TTL")); ttl != "" {
+		parsed, err := time.ParseDuration(ttl)
+		if err != nil || parsed < 0 {
+			return nil, &ValidationError{Field: "cache-ttl", Value: ttl, Err: fmt.Errorf("expected a non-negative duration such as 15m or 2h")}
+		}
+		config.CacheTTL = parsed
+	}
+	if config.CacheOnly && config.CacheDir == "" {
+		return nil, &ValidationError{Field: "offline", Value: "true", Err: fmt.Errorf("requires a cache directory (--cache-dir or JIRA_CACHE_DIR)")}
+	}
+
+	// Load JIRA credentials only if not in extract-only, markdown, schema or migrate mode, an offline subcommand,
+	// or replaying a cassette
+	isOfflineCommand := flags.Offline || flags.Replay != "" ||
+		(len(args) > 0 && (args[0] == SchemaCommand || args[0] == MigrateCommand))
+	if config.CacheOnly && !isOfflineCommand {
+		// Cached issues are keyed by instance, so --offline still needs the URL but no credentials
+		config.JIRAURL = getOrDefault(os.Getenv("JIRA_URL"), profile.JIRAURL)
+		if config.JIRAURL == "" {
+			return nil, &ValidationError{Field: "JIRA_URL", Value: "", Err: fmt.Errorf("environment variable is required")}
+		}
+	} else if !config.ExtractOnly && !config.ExtractFromGit && !flags.GenerateMarkdown && !isOfflineCommand {
+		config.JIRAURL = getOrDefault(os.Getenv("JIRA_URL"), profile.JIRAURL)
+		config.JIRAUsername = getOrDefault(os.Getenv("JIRA_USERNAME"), profile.JIRAUsername)
+
+		// The token may come from the environment, a token file, a credential helper or netrc
+		creds, err := resolveCredentials(config.JIRAURL, config.JIRAUsername, credentialSourcesFromEnv(profile))
+		if err != nil {
+			return nil, err
+		}
+		config.JIRAToken = creds.Token
+		config.JIRAUsername = creds.Username
 
 		// Validate JIRA configuration
 		if err := validateJIRAConfig(config); err != nil {
@@ -89,7 +267,8 @@ func LoadConfig(flags *FlagConfig, args []string) (*AppConfig, error) {
 // validateJIRAConfig validates JIRA-related configuration
 func validateJIRAConfig(config *AppConfig) error {
 	if config.JIRAToken == "" {
-		return &ValidationError{Field: "JIRA_API_TOKEN", Value: "", Err: fmt.Errorf("environment variable is required")}
+		return &ValidationError{Field: "JIRA_API_TOKEN", Value: "", Err: fmt.Errorf(
+			"environment variable is required (or JIRA_API_TOKEN_FILE, JIRA_CREDENTIAL_HELPER or a netrc entry)")}
 	}
 	if config.JIRAURL == "" {
 		return &ValidationError{Field: "JIRA_URL", Value: "", Err: fmt.Errorf("environment variable is required")}
@@ -110,15 +289,56 @@ func getOrDefault(values ...string) string {
 	return ""
 }
 
+// metricsOptions builds the metrics options from the configured status lists
+func (c *AppConfig) metricsOptions(now time.Time) MetricsOptions {
+	return MetricsOptions{
+		DoneStatuses:       c.DoneStatuses,
+		InProgressStatuses: c.InProgressStatuses,
+		Now:                now,
+	}
+}
+
+// referenceTime returns the instant the evidence describes: the --as-of time when set, otherwise now
+func (c *AppConfig) referenceTime(now time.Time) time.Time {
+	if !c.AsOf.IsZero() {
+		return c.AsOf
+	}
+	return now
+}
+
+// parseFieldList splits a comma-separated field list, dropping blanks; "none" yields an empty list
+func parseFieldList(value string) []string {
+	fields := []string{}
+	if strings.EqualFold(strings.TrimSpace(value), "none") {
+		return fields
+	}
+	for _, field := range strings.Split(value, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
 // DisplayUsage shows the usage information
 func DisplayUsage() {
 	fmt.Println("JIRA Evidence Gathering Tool")
 	fmt.Println("")
 	fmt.Println("Usage:")
+	fmt.Println("  ./main <command> [flags] [arguments]")
+	fmt.Println("")
+	fmt.Println("Commands:")
+	for _, cmd := range commands {
+		fmt.Printf("  %-13s %s\n", cmd.Name, cmd.Summary)
+	}
+	fmt.Printf("  %-13s %s\n", HelpCommand, "Show help for a command, e.g. './main help fetch'")
+	fmt.Println("")
+	fmt.Println("Legacy usage (without a command, still supported):")
 	fmt.Println("  ./main [OPTIONS] <start_commit>")
 	fmt.Println("  ./main <jira_id1> [jira_id2] [jira_id3] ...")
+	fmt.Println("  ./main [-o output_json] migrate <input_json>  # Upgrade old evidence to the current schema")
 	fmt.Println("")
-	fmt.Println("Options:")
+	fmt.Println("Options (legacy usage; run './main help <command>' for the flags of a command):")
 	fmt.Println("  -r, --regex PATTERN    JIRA ID regex pattern (default: '[A-Z]+-[0-9]+')")
 	fmt.Println("  -o, --output FILE      Output file for JIRA data (default: transformed_jira_data.json)")
 	fmt.Println("  --extract-only         Only extract JIRA IDs, don't fetch details")
@@ -126,6 +346,15 @@ func DisplayUsage() {
 	fmt.Println("  --range                Process commits from the specified commit to HEAD (instead of single commit)")
 	fmt.Println("  --markdown             Generate markdown from existing JSON file")
 	fmt.Println("  --markdown-output FILE Output file for markdown (default: transformed_jira_data.md)")
+	fmt.Println("  --track-fields LIST    Changelog fields to record besides status (default: 'assignee,priority,Fix Version,Sprint')")
+	fmt.Println("  --done-statuses LIST   Statuses that count as done for lead/cycle time (default: 'Done,Closed,Resolved')")
+	fmt.Println("  --in-progress-statuses LIST  Statuses that start the cycle time (default: 'In Progress')")
+	fmt.Println("  --as-of TIMESTAMP      Report ticket state as of this timestamp, e.g. the build time (RFC 3339)")
+	fmt.Println("  --error-exit-codes MAP Exit codes per error class, e.g. 'unauthorized=3,permission_denied=3,*=1'")
+	fmt.Println("                         Classes: unauthorized, permission_denied, not_found, rate_limited,")
+	fmt.Println("                         bad_request, server_error, network_error, unknown (default: all 0)")
+	fmt.Println("  --config FILE          Config file with named profiles (default: .jira-helper.yaml if present)")
+	fmt.Println("  --profile NAME         Config file profile to use (default: the file's default_profile)")
 	fmt.Println("  -h, --help             Display this help message")
 	fmt.Println("")
 	fmt.Println("Arguments:")
@@ -134,17 +363,40 @@ func DisplayUsage() {
 	fmt.Println("")
 	fmt.Println("Environment Variables:")
 	fmt.Println("  JIRA_API_TOKEN         JIRA API token")
+	fmt.Println("  JIRA_API_TOKEN_FILE   File containing the JIRA API token (used when JIRA_API_TOKEN is unset)")
+	fmt.Println("  JIRA_CREDENTIAL_HELPER  git-credential style helper command that prints the token as 'password='")
+	fmt.Println("  NETRC                 netrc file with a 'machine <jira host>' entry (default: ~/.netrc)")
 	fmt.Println("  JIRA_URL              JIRA instance URL")
 	fmt.Println("  JIRA_USERNAME         JIRA username")
 	fmt.Println("  JIRA_ID_REGEX         JIRA ID regex pattern (can be overridden with -r)")
 	fmt.Println("  OUTPUT_FILE           Output file path (can be overridden with -o)")
+	fmt.Println("  JIRA_TRACKED_FIELDS   Changelog fields to record (can be overridden with --track-fields)")
+	fmt.Println("  JIRA_DONE_STATUSES    Done statuses for metrics (can be overridden with --done-statuses)")
+	fmt.Println("  JIRA_IN_PROGRESS_STATUSES  In-progress statuses for metrics (can be overridden with --in-progress-statuses)")
+	fmt.Println("  JIRA_ERROR_EXIT_CODES Exit codes per error class (can be overridden with --error-exit-codes)")
+	fmt.Println("  JIRA_HELPER_CONFIG    Config file path (can be overridden with --config)")
+	fmt.Println("  JIRA_HELPER_PROFILE   Config file profile (can be overridden with --profile)")
+	fmt.Println("  JIRA_CACHE_DIR        Issue cache directory (can be overridden with --cache-dir)")
+	fmt.Println("  JIRA_CACHE_TTL        Issue cache TTL, e.g. 15m (can be overridden with --cache-ttl)")
+	fmt.Println("  JIRA_SORT_TASKS       Ticket order: key, created, updated, status or none (can be overridden with --sort-tasks)")
+	fmt.Println("  SOURCE_DATE_EPOCH     Pin generated timestamps (Unix seconds) and sort tickets by key, for reproducible output")
+	fmt.Println("")
+	fmt.Println("  Precedence: flags > environment variables > config file profile > defaults")
 	fmt.Println("")
 	fmt.Println("Examples:")
+	fmt.Println("  ./main fetch EV-123 EV-456             # Fetch tickets")
+	fmt.Println("  ./main fetch --range --commit abc123   # Fetch tickets referenced from abc123 to HEAD")
+	fmt.Println("  ./main extract abc123                  # Print the JIRA IDs of commit abc123")
+	fmt.Println("  ./main report -o report.md             # Render markdown from transformed_jira_data.json")
+	fmt.Println("  ./main gate --allow-errors             # Fail unless every ticket is done")
+	fmt.Println("")
+	fmt.Println("Legacy examples:")
 	fmt.Println("  ./main abc123def456                   # Process only commit abc123def456")
 	fmt.Println("  ./main --range abc123def456           # Process commits from abc123def456 to HEAD")
 	fmt.Println("  ./main -r 'EV-\\d+' -o jira_results.json abc123def456")
 	fmt.Println("  ./main --extract-only abc123def456")
 	fmt.Println("  ./main EV-123 EV-456 EV-789         # Direct JIRA ticket processing")
+	fmt.Println("  ./main --as-of 2025-01-31T18:00:00Z EV-123  # Ticket state at build time")
 	fmt.Println("  ./main --markdown                    # Generate markdown from transformed_jira_data.json")
 	fmt.Println("  ./main --markdown --markdown-output report.md  # Generate markdown with custom output file")
 }
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultConfigFile is read from the working directory when no config file is given
+const DefaultConfigFile = ".jira-helper.yaml"
+
+// Profile holds the settings of one JIRA instance from the config file.
+// Values are kept in their command line form so they slot into the flags > env > profile > defaults chain.
+type Profile struct {
+	Name               string
+	JIRAURL            string
+	JIRAUsername       string
+	JIRAIDRegex        string
+	OutputFile         string
+	TrackedFields      string
+	DoneStatuses       string
+	InProgressStatuses string
+	ErrorExitCodes     string
+	SortTasks          string
+	APITokenFile       string
+	CredentialHelper   string
+}
+
+// ConfigFile is a parsed .jira-helper.yaml
+type ConfigFile struct {
+	Path           string
+	DefaultProfile string
+	Profiles       map[string]*Profile
+}
+
+// profileKeys maps every key allowed in a profile to the parser that stores it
+var profileKeys = map[string]func(profile *Profile, value string){
+	"url":                  func(p *Profile, v string) { p.JIRAURL = v },
+	"username":             func(p *Profile, v string) { p.JIRAUsername = v },
+	"id_regex":             func(p *Profile, v string) { p.JIRAIDRegex = v },
+	"output_file":          func(p *Profile, v string) { p.OutputFile = v },
+	"tracked_fields":       func(p *Profile, v string) { p.TrackedFields = v },
+	"done_statuses":        func(p *Profile, v string) { p.DoneStatuses = v },
+	"in_progress_statuses": func(p *Profile, v string) { p.InProgressStatuses = v },
+	"error_exit_codes":     func(p *Profile, v string) { p.ErrorExitCodes = v },
+	"sort_tasks":           func(p *Profile, v string) { p.SortTasks = v },
+	"api_token_file":       func(p *Profile, v string) { p.APITokenFile = v },
+	"credential_helper":    func(p *Profile, v string) { p.CredentialHelper = v },
+}
+
+// yamlLinePattern extracts the line number from yaml syntax errors
+var yamlLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// loadProfile returns the profile selected by --profile, JIRA_HELPER_PROFILE or the file's default_profile.
+// Without a config file, or when no profile is selected, it returns an empty profile.
+func loadProfile(flags *FlagConfig) (*Profile, error) {
+	path := getOrDefault(flags.ConfigFile, os.Getenv("JIRA_HELPER_CONFIG"))
+	explicit := path != ""
+	if !explicit {
+		path = DefaultConfigFile
+	}
+	name := getOrDefault(flags.Profile, os.Getenv("JIRA_HELPER_PROFILE"))
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && os.IsNotExist(err) {
+			if name != "" {
+				return nil, &ValidationError{Field: "profile", Value: name, Err: fmt.Errorf("config file %s not found", path)}
+			}
+			return &Profile{}, nil
+		}
+		return nil, &ValidationError{Field: "config", Value: path, Err: err}
+	}
+
+	file, err := parseConfigFile(path, data)
+	if err != nil {
+		return nil, err
+	}
+	return file.selectProfile(name)
+}
+
+// selectProfile returns the named profile, falling back to default_profile
+func (c *ConfigFile) selectProfile(name string) (*Profile, error) {
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" {
+		return &Profile{}, nil
+	}
+
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return nil, &ValidationError{
+			Field: "profile",
+			Value: name,
+			Err:   fmt.Errorf("profile not defined (available: %s)", strings.Join(c.profileNames(), ", ")),
+			File:  c.Path,
+		}
+	}
+	return profile, nil
+}
+
+func (c *ConfigFile) profileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseConfigFile parses and validates config file data; every error carries the file and line
+func parseConfigFile(path string, data []byte) (*ConfigFile, error) {
+	file := &ConfigFile{Path: path, Profiles: make(map[string]*Profile)}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		line := 0
+		if match := yamlLinePattern.FindStringSubmatch(err.Error()); match != nil {
+			line, _ = strconv.Atoi(match[1])
+		}
+		return nil, &ValidationError{Field: "config", Value: path, Err: err, File: path, Line: line}
+	}
+	if len(root.Content) == 0 {
+		return file, nil // Empty file
+	}
+
+	doc := root.Content[0]
+	if doc.Kind != yaml.MappingNode {
+		return nil, configError(path, "config", doc, fmt.Errorf("expected a mapping"))
+	}
+
+	var defaultProfileNode *yaml.Node
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		key, value := doc.Content[i], doc.Content[i+1]
+		switch key.Value {
+		case "default_profile":
+			if value.Kind != yaml.ScalarNode {
+				return nil, configError(path, key.Value, value, fmt.Errorf("expected a profile name"))
+			}
+			file.DefaultProfile = value.Value
+			defaultProfileNode = value
+		case "profiles":
+			if value.Kind != yaml.MappingNode {
+				return nil, configError(path, key.Value, value, fmt.Errorf("expected a mapping of profile names"))
+			}
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				name := value.Content[j].Value
+				profile, err := parseProfile(path, name, value.Content[j+1])
+				if err != nil {
+					return nil, err
+				}
+				file.Profiles[name] = profile
+			}
+		default:
+			return nil, configError(path, key.Value, key, fmt.Errorf("unknown key (expected default_profile or profiles)"))
+		}
+	}
+
+	if file.DefaultProfile != "" {
+		if _, ok := file.Profiles[file.DefaultProfile]; !ok {
+			return nil, configError(path, "default_profile", defaultProfileNode, fmt.Errorf("profile not defined"))
+		}
+	}
+	return file, nil
+}
+
+// parseProfile parses and validates one profile mapping
+func parseProfile(path, name string, node *yaml.Node) (*Profile, error) {
+	profile := &Profile{Name: name}
+	if node.Kind != yaml.MappingNode {
+		return nil, configError(path, "profiles."+name, node, fmt.Errorf("expected a mapping"))
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		field := "profiles." + name + "." + key.Value
+
+		store, ok := profileKeys[key.Value]
+		if !ok {
+			return nil, configError(path, field, key, fmt.Errorf("unknown key"))
+		}
+
+		var text string
+		var err error
+		switch key.Value {
+		case "tracked_fields", "done_statuses", "in_progress_statuses":
+			text, err = listValue(value)
+		case "error_exit_codes":
+			text, err = exitCodesValue(value)
+		default:
+			if value.Kind != yaml.ScalarNode {
+				err = fmt.Errorf("expected a string")
+			}
+			text = value.Value
+		}
+		if err == nil {
+			err = validateProfileValue(key.Value, text)
+		}
+		if err != nil {
+			return nil, configError(path, field, value, err)
+		}
+		store(profile, text)
+	}
+	return profile, nil
+}
+
+// validateProfileValue checks a profile value the same way its flag would be checked
+func validateProfileValue(key, value string) error {
+	switch key {
+	case "url":
+		parsed, err := url.Parse(value)
+		if err != nil {
+			return err
+		}
+		if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("expected an http(s) URL")
+		}
+		if parsed.User != nil {
+			return fmt.Errorf("credentials must not be embedded in the URL")
+		}
+	case "id_regex":
+		if _, err := regexp.Compile(value); err != nil {
+			return err
+		}
+	case "error_exit_codes":
+		if _, err := parseErrorExitCodes(value); err != nil {
+			return err
+		}
+	case "sort_tasks":
+		if _, err := parseTaskOrder(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listValue accepts a YAML list or a comma-separated string; an empty list means "none"
+func listValue(node *yaml.Node) (string, error) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Value, nil
+	case yaml.SequenceNode:
+		if len(node.Content) == 0 {
+			return "none", nil
+		}
+		items := make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				return "", fmt.Errorf("expected a list of strings")
+			}
+			items = append(items, item.Value)
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return "", fmt.Errorf("expected a list of strings")
+	}
+}
+
+// exitCodesValue accepts a mapping of error class to exit code, or the --error-exit-codes string form
+func exitCodesValue(node *yaml.Node) (string, error) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Value, nil
+	case yaml.MappingNode:
+		pairs := make([]string, 0, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			pairs = append(pairs, node.Content[i].Value+"="+node.Content[i+1].Value)
+		}
+		sort.Strings(pairs)
+		return strings.Join(pairs, ","), nil
+	default:
+		return "", fmt.Errorf("expected a mapping of error class to exit code")
+	}
+}
+
+func configError(path, field string, node *yaml.Node, err error) error {
+	return &ValidationError{Field: field, Value: node.Value, Err: err, File: path, Line: node.Line}
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigFile = `default_profile: cloud
+profiles:
+  cloud:
+    url: https://example.atlassian.net
+    username: ci@example.com
+    id_regex: "EV-[0-9]+"
+    tracked_fields: [assignee, Sprint]
+    error_exit_codes:
+      unauthorized: 3
+      "*": 1
+  onprem:
+    url: https://jira.internal.example.com
+    username: svc-jira
+    id_regex: "OPS-[0-9]+"
+    output_file: onprem.json
+    done_statuses: "Done, Shipped"
+    tracked_fields: []
+`
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), DefaultConfigFile)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func clearConfigEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{"JIRA_HELPER_CONFIG", "JIRA_HELPER_PROFILE", "JIRA_ID_REGEX", "OUTPUT_FILE",
+		"JIRA_TRACKED_FIELDS", "JIRA_DONE_STATUSES", "JIRA_IN_PROGRESS_STATUSES", "JIRA_ERROR_EXIT_CODES",
+		"JIRA_API_TOKEN", "JIRA_URL", "JIRA_USERNAME", "JIRA_CACHE_DIR", "JIRA_CACHE_TTL", "JIRA_SORT_TASKS", SourceDateEpochEnv} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestParseConfigFile(t *testing.T) {
+	file, err := parseConfigFile(DefaultConfigFile, []byte(testConfigFile))
+	require.NoError(t, err)
+
+	assert.Equal(t, "cloud", file.DefaultProfile)
+	assert.Equal(t, []string{"cloud", "onprem"}, file.profileNames())
+
+	assert.Equal(t, &Profile{
+		Name:           "cloud",
+		JIRAURL:        "https://example.atlassian.net",
+		JIRAUsername:   "ci@example.com",
+		JIRAIDRegex:    "EV-[0-9]+",
+		TrackedFields:  "assignee,Sprint",
+		ErrorExitCodes: "*=1,unauthorized=3",
+	}, file.Profiles["cloud"])
+
+	assert.Equal(t, &Profile{
+		Name:          "onprem",
+		JIRAURL:       "https://jira.internal.example.com",
+		JIRAUsername:  "svc-jira",
+		JIRAIDRegex:   "OPS-[0-9]+",
+		OutputFile:    "onprem.json",
+		DoneStatuses:  "Done, Shipped",
+		TrackedFields: "none",
+	}, file.Profiles["onprem"])
+}
+
+func TestParseConfigFileErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expectedField string
+		expectedLine  int
+		errorContains string
+	}{
+		{
+			name:          "Invalid YAML",
+			content:       "profiles:\n  cloud: [\n",
+			expectedField: "config",
+			expectedLine:  2,
+		},
+		{
+			name:          "Unknown top-level key",
+			content:       "profiles: {}\nprofile: cloud\n",
+			expectedField: "profile",
+			expectedLine:  2,
+			errorContains: "unknown key",
+		},
+		{
+			name:          "Unknown profile key",
+			content:       "profiles:\n  cloud:\n    url: https://example.atlassian.net\n    regex: EV-1\n",
+			expectedField: "profiles.cloud.regex",
+			expectedLine:  4,
+			errorContains: "unknown key",
+		},
+		{
+			name:          "Invalid regex",
+			content:       "profiles:\n  cloud:\n    id_regex: \"[A-Z\"\n",
+			expectedField: "profiles.cloud.id_regex",
+			expectedLine:  3,
+			errorContains: "missing closing ]",
+		},
+		{
+			name:          "Invalid URL",
+			content:       "profiles:\n  cloud:\n    url: example.atlassian.net\n",
+			expectedField: "profiles.cloud.url",
+			expectedLine:  3,
+			errorContains: "expected an http(s) URL",
+		},
+		{
+			name:          "Invalid exit code class",
+			content:       "profiles:\n  cloud:\n    error_exit_codes:\n      forbidden: 3\n",
+			expectedField: "profiles.cloud.error_exit_codes",
+			expectedLine:  4,
+			errorContains: "forbidden",
+		},
+		{
+			name:          "Invalid task order",
+			content:       "profiles:\n  cloud:\n    sort_tasks: priority\n",
+			expectedField: "profiles.cloud.sort_tasks",
+			expectedLine:  3,
+			errorContains: "expected one of",
+		},
+		{
+			name:          "List of mappings",
+			content:       "profiles:\n  cloud:\n    done_statuses:\n      - name: Done\n",
+			expectedField: "profiles.cloud.done_statuses",
+			expectedLine:  4,
+			errorContains: "expected a list of strings",
+		},
+		{
+			name:          "Undefined default profile",
+			content:       "default_profile: cloud\nprofiles:\n  onprem: {}\n",
+			expectedField: "default_profile",
+			expectedLine:  1,
+			errorContains: "profile not defined",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseConfigFile("jira.yaml", []byte(tt.content))
+			require.Error(t, err)
+
+			var validationErr *ValidationError
+			require.True(t, errors.As(err, &validationErr))
+			assert.Equal(t, "jira.yaml", validationErr.File)
+			assert.Equal(t, tt.expectedField, validationErr.Field)
+			assert.Equal(t, tt.expectedLine, validationErr.Line)
+			if tt.errorContains != "" {
+				assert.Contains(t, err.Error(), tt.errorContains)
+			}
+		})
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	clearConfigEnv(t)
+	path := writeConfigFile(t, testConfigFile)
+
+	t.Run("Default profile", func(t *testing.T) {
+		profile, err := loadProfile(&FlagConfig{ConfigFile: path})
+		require.NoError(t, err)
+		assert.Equal(t, "cloud", profile.Name)
+	})
+
+	t.Run("Profile flag", func(t *testing.T) {
+		profile, err := loadProfile(&FlagConfig{ConfigFile: path, Profile: "onprem"})
+		require.NoError(t, err)
+		assert.Equal(t, "onprem", profile.Name)
+	})
+
+	t.Run("Profile and file from environment", func(t *testing.T) {
+		t.Setenv("JIRA_HELPER_CONFIG", path)
+		t.Setenv("JIRA_HELPER_PROFILE", "onprem")
+		profile, err := loadProfile(&FlagConfig{})
+		require.NoError(t, err)
+		assert.Equal(t, "onprem", profile.Name)
+	})
+
+	t.Run("Unknown profile", func(t *testing.T) {
+		_, err := loadProfile(&FlagConfig{ConfigFile: path, Profile: "staging"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "profile not defined (available: cloud, onprem)")
+		assert.Contains(t, err.Error(), path)
+	})
+
+	t.Run("Missing explicit file", func(t *testing.T) {
+		_, err := loadProfile(&FlagConfig{ConfigFile: filepath.Join(t.TempDir(), "missing.yaml")})
+		assert.Error(t, err)
+	})
+
+	t.Run("No default file", func(t *testing.T) {
+		oldDir, err := os.Getwd()
+		require.NoError(t, err)
+		require.NoError(t, os.Chdir(t.TempDir()))
+		defer os.Chdir(oldDir)
+
+		profile, err := loadProfile(&FlagConfig{})
+		require.NoError(t, err)
+		assert.Equal(t, &Profile{}, profile)
+
+		_, err = loadProfile(&FlagConfig{Profile: "cloud"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "config file .jira-helper.yaml not found")
+	})
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	clearConfigEnv(t)
+	path := writeConfigFile(t, testConfigFile)
+	t.Setenv("JIRA_API_TOKEN", "token")
+
+	t.Run("Profile overrides defaults", func(t *testing.T) {
+		config, err := LoadConfig(&FlagConfig{ConfigFile: path, Profile: "onprem"}, []string{})
+		require.NoError(t, err)
+
+		assert.Equal(t, "onprem", config.Profile)
+		assert.Equal(t, "OPS-[0-9]+", config.JIRAIDRegex)
+		assert.Equal(t, "onprem.json", config.OutputFile)
+		assert.Equal(t, "https://jira.internal.example.com", config.JIRAURL)
+		assert.Equal(t, []string{"Done", "Shipped"}, config.DoneStatuses)
+		assert.Equal(t, []string{}, config.TrackedFields)
+		assert.Equal(t, parseFieldList(DefaultInProgressStatuses), config.InProgressStatuses)
+	})
+
+	t.Run("Environment overrides profile", func(t *testing.T) {
+		t.Setenv("JIRA_ID_REGEX", "ENV-[0-9]+")
+		t.Setenv("JIRA_URL", "https://env.atlassian.net")
+		config, err := LoadConfig(&FlagConfig{ConfigFile: path}, []string{})
+		require.NoError(t, err)
+
+		assert.Equal(t, "ENV-[0-9]+", config.JIRAIDRegex)
+		assert.Equal(t, "https://env.atlassian.net", config.JIRAURL)
+		assert.Equal(t, "ci@example.com", config.JIRAUsername)
+		assert.Equal(t, map[string]int{"*": 1, ErrorCodeUnauthorized: 3}, config.ErrorExitCodes)
+	})
+
+	t.Run("Flags override environment and profile", func(t *testing.T) {
+		t.Setenv("JIRA_ID_REGEX", "ENV-[0-9]+")
+		flags := &FlagConfig{ConfigFile: path, JIRAIDRegex: "FLAG-[0-9]+", TrackFields: "Sprint"}
+		config, err := LoadConfig(flags, []string{})
+		require.NoError(t, err)
+
+		assert.Equal(t, "FLAG-[0-9]+", config.JIRAIDRegex)
+		assert.Equal(t, []string{"Sprint"}, config.TrackedFields)
+	})
+
+	t.Run("Invalid config file", func(t *testing.T) {
+		invalid := writeConfigFile(t, "profiles:\n  cloud:\n    id_regex: \"[A-Z\"\n")
+		_, err := LoadConfig(&FlagConfig{ConfigFile: invalid, ExtractOnly: true}, []string{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), invalid+":3:")
+	})
+}
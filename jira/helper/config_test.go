@@ -150,10 +150,15 @@ func TestLoadConfigComplete(t *testing.T) {
 			envVars:     map[string]string{},
 			expectError: false,
 			expectedConfig: &AppConfig{
-				JIRAIDRegex:  "TEST-[0-9]+",
-				OutputFile:   "test.json",
-				ExtractOnly:  true,
-				SingleCommit: true,
+				JIRAIDRegex:        "TEST-[0-9]+",
+				OutputFile:         "test.json",
+				EvidenceFile:       DefaultOutputFile,
+				ExtractOnly:        true,
+				SingleCommit:       true,
+				TrackedFields:      parseFieldList(DefaultTrackedFields),
+				DoneStatuses:       parseFieldList(DefaultDoneStatuses),
+				InProgressStatuses: parseFieldList(DefaultInProgressStatuses),
+				TaskOrder:          TaskOrderNone,
 			},
 		},
 		{
@@ -165,10 +170,15 @@ func TestLoadConfigComplete(t *testing.T) {
 			envVars:     map[string]string{},
 			expectError: false,
 			expectedConfig: &AppConfig{
-				JIRAIDRegex:    DefaultJIRAIDRegex,
-				OutputFile:     DefaultOutputFile,
-				ExtractFromGit: true,
-				SingleCommit:   true,
+				JIRAIDRegex:        DefaultJIRAIDRegex,
+				OutputFile:         DefaultOutputFile,
+				EvidenceFile:       DefaultOutputFile,
+				ExtractFromGit:     true,
+				SingleCommit:       true,
+				TrackedFields:      parseFieldList(DefaultTrackedFields),
+				DoneStatuses:       parseFieldList(DefaultDoneStatuses),
+				InProgressStatuses: parseFieldList(DefaultInProgressStatuses),
+				TaskOrder:          TaskOrderNone,
 			},
 		},
 		{
@@ -181,9 +191,14 @@ func TestLoadConfigComplete(t *testing.T) {
 			envVars:     map[string]string{},
 			expectError: false,
 			expectedConfig: &AppConfig{
-				JIRAIDRegex:  DefaultJIRAIDRegex,
-				OutputFile:   DefaultOutputFile,
-				SingleCommit: true,
+				JIRAIDRegex:        DefaultJIRAIDRegex,
+				OutputFile:         DefaultOutputFile,
+				EvidenceFile:       DefaultOutputFile,
+				SingleCommit:       true,
+				TrackedFields:      parseFieldList(DefaultTrackedFields),
+				DoneStatuses:       parseFieldList(DefaultDoneStatuses),
+				InProgressStatuses: parseFieldList(DefaultInProgressStatuses),
+				TaskOrder:          TaskOrderNone,
 			},
 		},
 		{
@@ -197,14 +212,41 @@ func TestLoadConfigComplete(t *testing.T) {
 			},
 			expectError: false,
 			expectedConfig: &AppConfig{
-				JIRAToken:    "token123",
-				JIRAURL:      "https://example.atlassian.net",
-				JIRAUsername: "user@example.com",
-				JIRAIDRegex:  DefaultJIRAIDRegex,
-				OutputFile:   DefaultOutputFile,
-				SingleCommit: true,
+				JIRAToken:          "token123",
+				JIRAURL:            "https://example.atlassian.net",
+				JIRAUsername:       "user@example.com",
+				JIRAIDRegex:        DefaultJIRAIDRegex,
+				OutputFile:         DefaultOutputFile,
+				EvidenceFile:       DefaultOutputFile,
+				SingleCommit:       true,
+				TrackedFields:      parseFieldList(DefaultTrackedFields),
+				DoneStatuses:       parseFieldList(DefaultDoneStatuses),
+				InProgressStatuses: parseFieldList(DefaultInProgressStatuses),
+				TaskOrder:          TaskOrderNone,
 			},
 		},
+		{
+			name: "Invalid as-of timestamp",
+			flags: &FlagConfig{
+				ExtractOnly: true,
+				AsOf:        "last tuesday",
+			},
+			args:          []string{},
+			envVars:       map[string]string{},
+			expectError:   true,
+			errorContains: "as-of",
+		},
+		{
+			name: "Invalid error exit codes",
+			flags: &FlagConfig{
+				ExtractOnly:    true,
+				ErrorExitCodes: "teapot=1",
+			},
+			args:          []string{},
+			envVars:       map[string]string{},
+			expectError:   true,
+			errorContains: "error-exit-codes",
+		},
 		{
 			name:  "Full mode with missing JIRA token",
 			flags: &FlagConfig{},
@@ -253,10 +295,15 @@ func TestLoadConfigComplete(t *testing.T) {
 			},
 			expectError: false,
 			expectedConfig: &AppConfig{
-				JIRAIDRegex:  "CUSTOM-[0-9]+",
-				OutputFile:   "custom_output.json",
-				ExtractOnly:  true,
-				SingleCommit: true,
+				JIRAIDRegex:        "CUSTOM-[0-9]+",
+				OutputFile:         "custom_output.json",
+				EvidenceFile:       "custom_output.json",
+				ExtractOnly:        true,
+				SingleCommit:       true,
+				TrackedFields:      parseFieldList(DefaultTrackedFields),
+				DoneStatuses:       parseFieldList(DefaultDoneStatuses),
+				InProgressStatuses: parseFieldList(DefaultInProgressStatuses),
+				TaskOrder:          TaskOrderNone,
 			},
 		},
 		{
@@ -273,10 +320,15 @@ func TestLoadConfigComplete(t *testing.T) {
 			},
 			expectError: false,
 			expectedConfig: &AppConfig{
-				JIRAIDRegex:  "FLAG-[0-9]+",
-				OutputFile:   "flag_output.json",
-				ExtractOnly:  true,
-				SingleCommit: true,
+				JIRAIDRegex:        "FLAG-[0-9]+",
+				OutputFile:         "flag_output.json",
+				EvidenceFile:       "env_output.json",
+				ExtractOnly:        true,
+				SingleCommit:       true,
+				TrackedFields:      parseFieldList(DefaultTrackedFields),
+				DoneStatuses:       parseFieldList(DefaultDoneStatuses),
+				InProgressStatuses: parseFieldList(DefaultInProgressStatuses),
+				TaskOrder:          TaskOrderNone,
 			},
 		},
 		{
@@ -289,10 +341,15 @@ func TestLoadConfigComplete(t *testing.T) {
 			envVars:     map[string]string{},
 			expectError: false,
 			expectedConfig: &AppConfig{
-				JIRAIDRegex:  DefaultJIRAIDRegex,
-				OutputFile:   DefaultOutputFile,
-				ExtractOnly:  true,
-				SingleCommit: false,
+				JIRAIDRegex:        DefaultJIRAIDRegex,
+				OutputFile:         DefaultOutputFile,
+				EvidenceFile:       DefaultOutputFile,
+				ExtractOnly:        true,
+				SingleCommit:       false,
+				TrackedFields:      parseFieldList(DefaultTrackedFields),
+				DoneStatuses:       parseFieldList(DefaultDoneStatuses),
+				InProgressStatuses: parseFieldList(DefaultInProgressStatuses),
+				TaskOrder:          TaskOrderNone,
 			},
 		},
 		{
@@ -312,12 +369,17 @@ func TestLoadConfigComplete(t *testing.T) {
 			},
 			expectError: false,
 			expectedConfig: &AppConfig{
-				JIRAToken:    "token456",
-				JIRAURL:      "https://test.atlassian.net",
-				JIRAUsername: "test@example.com",
-				JIRAIDRegex:  "FLAG-[0-9]+", // Flag overrides env
-				OutputFile:   "flag.json",   // Flag overrides env
-				SingleCommit: false,         // CommitRange flag
+				JIRAToken:          "token456",
+				JIRAURL:            "https://test.atlassian.net",
+				JIRAUsername:       "test@example.com",
+				JIRAIDRegex:        "FLAG-[0-9]+", // Flag overrides env
+				OutputFile:         "flag.json",   // Flag overrides env
+				EvidenceFile:       "env.json",
+				SingleCommit:       false, // CommitRange flag
+				TrackedFields:      parseFieldList(DefaultTrackedFields),
+				DoneStatuses:       parseFieldList(DefaultDoneStatuses),
+				InProgressStatuses: parseFieldList(DefaultInProgressStatuses),
+				TaskOrder:          TaskOrderNone,
 			},
 		},
 	}
@@ -373,7 +435,7 @@ func TestDisplayUsageComplete(t *testing.T) {
 		"Usage:",
 		"./main [OPTIONS] <start_commit>",
 		"./main <jira_id1> [jira_id2] [jira_id3] ...",
-		"Options:",
+		"Options (legacy usage",
 		"-r, --regex PATTERN",
 		"-o, --output FILE",
 		"--extract-only",
@@ -548,3 +610,38 @@ func TestValidateJIRAConfigComplete(t *testing.T) {
 		})
 	}
 }
+
+func TestParseFieldList(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "Default fields",
+			input:    DefaultTrackedFields,
+			expected: []string{"assignee", "priority", "Fix Version", "Sprint"},
+		},
+		{
+			name:     "Blanks and spaces are dropped",
+			input:    " assignee , ,labels ",
+			expected: []string{"assignee", "labels"},
+		},
+		{
+			name:     "None disables tracking",
+			input:    "None",
+			expected: []string{},
+		},
+		{
+			name:     "Empty string",
+			input:    "",
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseFieldList(tt.input))
+		})
+	}
+}
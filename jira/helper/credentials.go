@@ -0,0 +1,315 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Constants for credential sourcing
+const (
+	// RedactedPlaceholder replaces secrets in output and errors
+	RedactedPlaceholder = "[REDACTED]"
+	// CredentialHelperTimeout bounds how long a credential helper may run
+	CredentialHelperTimeout = 30 * time.Second
+)
+
+// Credential sources, reported so users can tell where a token came from without seeing it
+const (
+	CredentialSourceEnv    = "JIRA_API_TOKEN"
+	CredentialSourceFile   = "JIRA_API_TOKEN_FILE"
+	CredentialSourceHelper = "credential helper"
+	CredentialSourceNetrc  = "netrc"
+)
+
+// Credentials are the resolved JIRA username and API token
+type Credentials struct {
+	Username string
+	Token    string
+	Source   string
+}
+
+// CredentialSources configures where the API token is looked up, in field order
+type CredentialSources struct {
+	Token     string
+	TokenFile string
+	Helper    string
+	NetrcFile string
+}
+
+// credentialSourcesFromEnv reads the credential sources from the environment, falling back to the profile
+func credentialSourcesFromEnv(profile *Profile) CredentialSources {
+	return CredentialSources{
+		Token:     os.Getenv("JIRA_API_TOKEN"),
+		TokenFile: getOrDefault(os.Getenv("JIRA_API_TOKEN_FILE"), profile.APITokenFile),
+		Helper:    getOrDefault(os.Getenv("JIRA_CREDENTIAL_HELPER"), profile.CredentialHelper),
+		NetrcFile: getOrDefault(os.Getenv("NETRC"), defaultNetrcFile()),
+	}
+}
+
+// resolveCredentials returns the API token from the first source that has one: JIRA_API_TOKEN,
+// JIRA_API_TOKEN_FILE, the credential helper, then netrc. The helper and netrc may also supply the username.
+// An empty token without error means no source is configured. Every token found is registered for redaction.
+func resolveCredentials(jiraURL, username string, sources CredentialSources) (Credentials, error) {
+	creds := Credentials{Username: username}
+
+	switch {
+	case sources.Token != "":
+		creds.Token, creds.Source = sources.Token, CredentialSourceEnv
+
+	case sources.TokenFile != "":
+		data, err := os.ReadFile(sources.TokenFile)
+		if err != nil {
+			return creds, &ValidationError{Field: "JIRA_API_TOKEN_FILE", Value: sources.TokenFile, Err: err}
+		}
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return creds, &ValidationError{Field: "JIRA_API_TOKEN_FILE", Value: sources.TokenFile, Err: fmt.Errorf("file is empty")}
+		}
+		creds.Token, creds.Source = token, CredentialSourceFile
+
+	default:
+		if sources.Helper != "" {
+			helperUser, token, err := runCredentialHelper(sources.Helper, jiraURL, username)
+			if err != nil {
+				return creds, err
+			}
+			if token != "" {
+				creds.Token, creds.Source = token, CredentialSourceHelper
+				creds.Username = getOrDefault(username, helperUser)
+				break
+			}
+		}
+		if sources.NetrcFile != "" {
+			login, password, err := lookupNetrc(sources.NetrcFile, jiraURL)
+			if err != nil {
+				return creds, err
+			}
+			if password != "" {
+				creds.Token, creds.Source = password, CredentialSourceNetrc
+				creds.Username = getOrDefault(username, login)
+			}
+		}
+	}
+
+	registerSecret(creds.Token)
+	return creds, nil
+}
+
+// runCredentialHelper asks a git-credential style helper for the token. The helper is run through the shell
+// with a "get" argument, receives protocol/host/username as key=value lines on stdin, and answers with
+// key=value lines on stdout; "password" is the token and "username" may fill in a missing username.
+func runCredentialHelper(helper, jiraURL, username string) (string, string, error) {
+	var request bytes.Buffer
+	if parsed, err := url.Parse(jiraURL); err == nil && parsed.Host != "" {
+		fmt.Fprintf(&request, "protocol=%s\nhost=%s\n", parsed.Scheme, parsed.Host)
+	}
+	if username != "" {
+		fmt.Fprintf(&request, "username=%s\n", username)
+	}
+	request.WriteString("\n")
+
+	ctx, cancel := context.WithTimeout(context.Background(), CredentialHelperTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	// Like git, a leading "!" marks a shell snippet; both forms are run through the shell
+	cmd := exec.CommandContext(ctx, "sh", "-c", strings.TrimPrefix(helper, "!")+" get")
+	cmd.Stdin = &request
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// The helper's own output may echo secrets, so it is redacted before it reaches the error
+		detail := strings.TrimSpace(stderr.String())
+		if detail != "" {
+			err = fmt.Errorf("%v: %s", err, detail)
+		}
+		response := parseCredentialResponse(stdout.String())
+		registerSecret(response["password"])
+		return "", "", &ValidationError{Field: "JIRA_CREDENTIAL_HELPER", Value: helper, Err: fmt.Errorf("%s", redact(err.Error()))}
+	}
+
+	response := parseCredentialResponse(stdout.String())
+	return response["username"], response["password"], nil
+}
+
+// parseCredentialResponse parses the key=value lines of a credential helper response
+func parseCredentialResponse(output string) map[string]string {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			break
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// defaultNetrcFile returns ~/.netrc, or "" when the home directory is unknown
+func defaultNetrcFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".netrc")
+}
+
+// lookupNetrc returns the login and password of the netrc entry for the JIRA host, falling back to "default".
+// A missing netrc file is not an error.
+func lookupNetrc(path, jiraURL string) (string, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", "", nil
+		}
+		return "", "", &ValidationError{Field: "NETRC", Value: path, Err: err}
+	}
+
+	host, hostPort := "", ""
+	if parsed, err := url.Parse(jiraURL); err == nil {
+		host, hostPort = parsed.Hostname(), parsed.Host
+	}
+
+	type entry struct{ login, password string }
+	var matched, fallback, current *entry
+
+	tokens := strings.Fields(string(data))
+	for i := 0; i < len(tokens); i++ {
+		next := func() string {
+			if i+1 < len(tokens) {
+				i++
+				return tokens[i]
+			}
+			return ""
+		}
+
+		switch tokens[i] {
+		case "machine":
+			name := next()
+			current = &entry{}
+			if host != "" && matched == nil && (strings.EqualFold(name, host) || strings.EqualFold(name, hostPort)) {
+				matched = current
+			}
+		case "default":
+			current = &entry{}
+			if fallback == nil {
+				fallback = current
+			}
+		case "login":
+			if current != nil {
+				current.login = next()
+			}
+		case "password":
+			if current != nil {
+				current.password = next()
+				registerSecret(current.password)
+			}
+		case "account":
+			next()
+		case "macdef":
+			// Macro definitions run to the next blank line, which Fields cannot see; stop parsing here
+			i = len(tokens)
+		}
+	}
+
+	if matched != nil {
+		return matched.login, matched.password, nil
+	}
+	if fallback != nil {
+		return fallback.login, fallback.password, nil
+	}
+	return "", "", nil
+}
+
+// secretRegistry holds every secret value known to the process so output can be scrubbed
+var secretRegistry = struct {
+	sync.Mutex
+	values []string
+}{}
+
+// registerSecret adds a value to the set redacted from output and errors; short values are ignored
+// because replacing them would mangle ordinary text
+func registerSecret(secret string) {
+	secret = strings.TrimSpace(secret)
+	if len(secret) < 4 {
+		return
+	}
+
+	secretRegistry.Lock()
+	defer secretRegistry.Unlock()
+	for _, known := range secretRegistry.values {
+		if known == secret {
+			return
+		}
+	}
+	secretRegistry.values = append(secretRegistry.values, secret)
+	// Replace longer secrets first so one secret containing another is fully hidden
+	sort.Slice(secretRegistry.values, func(i, j int) bool {
+		return len(secretRegistry.values[i]) > len(secretRegistry.values[j])
+	})
+}
+
+// secretPatterns catch credentials that were never registered, such as tokens embedded in URLs or headers
+var secretPatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`(?i)(https?://[^:/@\s]+:)[^@\s]+@`), "${1}" + RedactedPlaceholder + "@"},
+	{regexp.MustCompile(`(?i)(authorization:\s*(?:basic|bearer)\s+)\S+`), "${1}" + RedactedPlaceholder},
+	{regexp.MustCompile(`(?i)((?:password|api_token|token)=)[^\s&]+`), "${1}" + RedactedPlaceholder},
+}
+
+// redact replaces every registered secret and credential-looking pattern in text
+func redact(text string) string {
+	secretRegistry.Lock()
+	for _, secret := range secretRegistry.values {
+		text = strings.ReplaceAll(text, secret, RedactedPlaceholder)
+	}
+	secretRegistry.Unlock()
+
+	for _, p := range secretPatterns {
+		text = p.pattern.ReplaceAllString(text, p.replacement)
+	}
+	return text
+}
+
+// redactError returns err with its message redacted, or nil
+func redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	redacted := redact(err.Error())
+	if redacted == err.Error() {
+		return err
+	}
+	return &redactedError{message: redacted, err: err}
+}
+
+// redactedError hides secrets in the message while keeping the original error available to errors.As
+type redactedError struct {
+	message string
+	err     error
+}
+
+func (e *redactedError) Error() string {
+	return e.message
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// DiffFailureExitCode is the exit code of the diff command when a change listed in --fail-on is found
+const DiffFailureExitCode = 1
+
+// Kinds of differences between two evidence snapshots, as accepted by --fail-on
+const (
+	DiffAdded     = "added"
+	DiffRemoved   = "removed"
+	DiffChanged   = "changed"
+	DiffRegressed = "regressed"
+	// DiffAny matches every kind
+	DiffAny = "any"
+)
+
+// diffKinds lists the kinds --fail-on accepts besides "any", in reporting order
+var diffKinds = []string{DiffAdded, DiffRemoved, DiffChanged, DiffRegressed}
+
+// EvidenceDiff is the difference in ticket state between two evidence snapshots, e.g. QA and PROD
+type EvidenceDiff struct {
+	From    string      `json:"from"`
+	To      string      `json:"to"`
+	Added   []TaskState `json:"added"`
+	Removed []TaskState `json:"removed"`
+	Changed []TaskDiff  `json:"changed"`
+}
+
+// TaskState identifies a ticket that only one snapshot contains
+type TaskState struct {
+	Key    string `json:"key"`
+	Status string `json:"status"`
+}
+
+// TaskDiff lists the changed values of a ticket both snapshots contain
+type TaskDiff struct {
+	Key string `json:"key"`
+	// Regressed is set when the ticket left the done statuses, e.g. Done to Reopened
+	Regressed bool          `json:"regressed"`
+	Changes   []ValueChange `json:"changes"`
+}
+
+// ValueChange is one field whose value differs between the snapshots
+type ValueChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// RegressedCount returns the number of tickets that left the done statuses
+func (d *EvidenceDiff) RegressedCount() int {
+	count := 0
+	for _, task := range d.Changed {
+		if task.Regressed {
+			count++
+		}
+	}
+	return count
+}
+
+// count returns the number of differences of a kind
+func (d *EvidenceDiff) count(kind string) int {
+	switch kind {
+	case DiffAdded:
+		return len(d.Added)
+	case DiffRemoved:
+		return len(d.Removed)
+	case DiffChanged:
+		return len(d.Changed)
+	case DiffRegressed:
+		return d.RegressedCount()
+	}
+	return 0
+}
+
+// diffEvidence compares two snapshots; tickets are matched by key (case-insensitive).
+// Added and changed tickets follow the order of the newer snapshot, removed ones that of the older.
+func diffEvidence(from, to TransitionCheckResponse, doneStatuses []string) *EvidenceDiff {
+	diff := &EvidenceDiff{Added: []TaskState{}, Removed: []TaskState{}, Changed: []TaskDiff{}}
+
+	fromTasks := make(map[string]JiraTransitionResult, len(from.Tasks))
+	for _, task := range from.Tasks {
+		fromTasks[strings.ToUpper(task.Key)] = task
+	}
+	toKeys := make(map[string]bool, len(to.Tasks))
+
+	for _, task := range to.Tasks {
+		key := strings.ToUpper(task.Key)
+		if toKeys[key] {
+			continue
+		}
+		toKeys[key] = true
+
+		previous, ok := fromTasks[key]
+		if !ok {
+			diff.Added = append(diff.Added, TaskState{Key: task.Key, Status: task.Status})
+			continue
+		}
+		if changes := diffTask(previous, task); len(changes) > 0 {
+			diff.Changed = append(diff.Changed, TaskDiff{
+				Key:       task.Key,
+				Regressed: isRegression(previous, task, doneStatuses),
+				Changes:   changes,
+			})
+		}
+	}
+
+	for _, task := range from.Tasks {
+		key := strings.ToUpper(task.Key)
+		if toKeys[key] {
+			continue
+		}
+		toKeys[key] = true
+		diff.Removed = append(diff.Removed, TaskState{Key: task.Key, Status: task.Status})
+	}
+
+	return diff
+}
+
+// diffTask returns the values that differ between two snapshots of a ticket
+func diffTask(from, to JiraTransitionResult) []ValueChange {
+	var changes []ValueChange
+	compare := func(field, fromValue, toValue string) {
+		if fromValue != toValue {
+			changes = append(changes, ValueChange{Field: field, From: fromValue, To: toValue})
+		}
+	}
+
+	compare("status", from.Status, to.Status)
+	compare("assignee", stringValue(from.Assignee), stringValue(to.Assignee))
+	compare("priority", from.Priority, to.Priority)
+	compare("type", from.Type, to.Type)
+	compare("error", taskErrorCode(from), taskErrorCode(to))
+
+	// Tracked field values are only present in --as-of evidence
+	fields := make(map[string]bool)
+	for field := range from.FieldValues {
+		fields[field] = true
+	}
+	for field := range to.FieldValues {
+		fields[field] = true
+	}
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+	for _, field := range names {
+		compare(field, from.FieldValues[field], to.FieldValues[field])
+	}
+
+	return changes
+}
+
+// isRegression reports whether a ticket moved out of the done statuses.
+// A ticket that could not be fetched is not a regression; its error shows up as a change instead.
+func isRegression(from, to JiraTransitionResult, doneStatuses []string) bool {
+	return statusIn(from.Status, doneStatuses) && !statusIn(to.Status, doneStatuses) && to.Status != ErrorStatus
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func taskErrorCode(task JiraTransitionResult) string {
+	if task.Error == nil {
+		return ""
+	}
+	return task.Error.Code
+}
+
+// parseDiffKinds parses the --fail-on list; "any" stands for every kind
+func parseDiffKinds(value string) ([]string, error) {
+	var kinds []string
+	for _, kind := range parseFieldList(value) {
+		kind = strings.ToLower(kind)
+		if kind == DiffAny {
+			return diffKinds, nil
+		}
+		if !containsString(diffKinds, kind) {
+			return nil, &ValidationError{Field: "fail-on", Value: kind,
+				Err: fmt.Errorf("expected one of %s or %s", strings.Join(diffKinds, ", "), DiffAny)}
+		}
+		kinds = append(kinds, kind)
+	}
+	return kinds, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// generateDiffMarkdown renders a diff as a markdown report
+func generateDiffMarkdown(diff *EvidenceDiff) string {
+	var sb strings.Builder
+
+	sb.WriteString("# JIRA Evidence Diff\n\n")
+	sb.WriteString(fmt.Sprintf("From: %s\n\n", diff.From))
+	sb.WriteString(fmt.Sprintf("To: %s\n\n", diff.To))
+	sb.WriteString(fmt.Sprintf("Added: %d, removed: %d, changed: %d (regressed: %d)\n\n",
+		len(diff.Added), len(diff.Removed), len(diff.Changed), diff.RegressedCount()))
+
+	writeStates := func(title string, states []TaskState) {
+		if len(states) == 0 {
+			return
+		}
+		sb.WriteString(fmt.Sprintf("## %s\n\n", title))
+		sb.WriteString("| Key | Status |\n")
+		sb.WriteString("|-----|--------|\n")
+		for _, state := range states {
+			sb.WriteString(fmt.Sprintf("| %s | %s |\n", state.Key, state.Status))
+		}
+		sb.WriteString("\n")
+	}
+	writeStates("Added Tickets", diff.Added)
+	writeStates("Removed Tickets", diff.Removed)
+
+	if len(diff.Changed) > 0 {
+		sb.WriteString("## Changed Tickets\n\n")
+		sb.WriteString("| Key | Field | From | To |\n")
+		sb.WriteString("|-----|-------|------|----|\n")
+		for _, task := range diff.Changed {
+			key := task.Key
+			if task.Regressed {
+				key += " ⚠️ regressed"
+			}
+			for _, change := range task.Changes {
+				sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", key, change.Field, diffValue(change.From), diffValue(change.To)))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(diff.Added) == 0 && len(diff.Removed) == 0 && len(diff.Changed) == 0 {
+		sb.WriteString("No differences in ticket state.\n")
+	}
+
+	return sb.String()
+}
+
+func diffValue(value string) string {
+	if value == "" {
+		return "_none_"
+	}
+	return value
+}
+
+// runDiffCommand compares two evidence files and prints or writes the diff
+func runDiffCommand(flags *FlagConfig, args []string, config *AppConfig) error {
+	if len(args) != 2 {
+		return fmt.Errorf("expected two evidence files (from and to), got %d arguments", len(args))
+	}
+	failOn, err := parseDiffKinds(flags.FailOn)
+	if err != nil {
+		return err
+	}
+
+	from, _, err := loadTransitionResponse(args[0])
+	if err != nil {
+		return err
+	}
+	to, _, err := loadTransitionResponse(args[1])
+	if err != nil {
+		return err
+	}
+
+	diff := diffEvidence(from, to, config.DoneStatuses)
+	diff.From = args[0]
+	diff.To = args[1]
+
+	var output []byte
+	switch strings.ToLower(getOrDefault(flags.Format, "markdown")) {
+	case "markdown", "md":
+		output = []byte(generateDiffMarkdown(diff))
+	case "json":
+		data, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshaling JSON: %v", err)
+		}
+		output = append(data, '\n')
+	default:
+		return &ValidationError{Field: "format", Value: flags.Format, Err: fmt.Errorf("expected markdown or json")}
+	}
+
+	if flags.OutputFile == "" {
+		os.Stdout.Write(output)
+	} else {
+		if err := writeToFile(flags.OutputFile, output); err != nil {
+			return fmt.Errorf("error writing to file: %v", err)
+		}
+		fmt.Printf("Evidence diff saved to: %s\n", flags.OutputFile)
+	}
+
+	var failed []string
+	for _, kind := range failOn {
+		if n := diff.count(kind); n > 0 {
+			failed = append(failed, fmt.Sprintf("%d %s", n, kind))
+		}
+	}
+	if len(failed) > 0 {
+		return &ExitCodeError{Code: DiffFailureExitCode, Err: fmt.Errorf("evidence differs: %s", strings.Join(failed, ", "))}
+	}
+	return nil
+}
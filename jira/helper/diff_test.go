@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffEvidence(t *testing.T) {
+	tests := []struct {
+		name            string
+		from            []JiraTransitionResult
+		to              []JiraTransitionResult
+		expectedAdded   []TaskState
+		expectedRemoved []TaskState
+		expectedChanged []TaskDiff
+	}{
+		{
+			name:            "Added and removed tickets",
+			from:            []JiraTransitionResult{{Key: "EV-3", Status: "In Progress"}},
+			to:              []JiraTransitionResult{{Key: "EV-5", Status: "To Do"}},
+			expectedAdded:   []TaskState{{Key: "EV-5", Status: "To Do"}},
+			expectedRemoved: []TaskState{{Key: "EV-3", Status: "In Progress"}},
+		},
+		{
+			name: "Done ticket reopened",
+			from: []JiraTransitionResult{{Key: "EV-1", Status: "Done", Type: "Bug", Priority: "High"}},
+			to:   []JiraTransitionResult{{Key: "EV-1", Status: "Reopened", Type: "Bug", Priority: "High"}},
+			expectedChanged: []TaskDiff{
+				{Key: "EV-1", Regressed: true, Changes: []ValueChange{{Field: "status", From: "Done", To: "Reopened"}}},
+			},
+		},
+		{
+			name: "Assignee changed",
+			from: []JiraTransitionResult{{Key: "EV-2", Status: "Done", Assignee: strPtr("Alice")}},
+			to:   []JiraTransitionResult{{Key: "EV-2", Status: "Done", Assignee: strPtr("Bob")}},
+			expectedChanged: []TaskDiff{
+				{Key: "EV-2", Changes: []ValueChange{{Field: "assignee", From: "Alice", To: "Bob"}}},
+			},
+		},
+		{
+			name: "Ticket could no longer be fetched",
+			from: []JiraTransitionResult{{Key: "EV-4", Status: "Done", Type: "Task"}},
+			to: []JiraTransitionResult{{Key: "EV-4", Status: ErrorStatus, Type: ErrorType,
+				Error: &TaskError{Code: ErrorCodeNotFound, HTTPStatus: 404}}},
+			expectedChanged: []TaskDiff{
+				{Key: "EV-4", Changes: []ValueChange{
+					{Field: "status", From: "Done", To: ErrorStatus},
+					{Field: "type", From: "Task", To: ErrorType},
+					{Field: "error", From: "", To: ErrorCodeNotFound},
+				}},
+			},
+		},
+		{
+			name: "Tracked field values",
+			from: []JiraTransitionResult{{Key: "EV-1", FieldValues: map[string]string{"Fix Version": "1.0", "Sprint": "S1"}}},
+			to:   []JiraTransitionResult{{Key: "EV-1", FieldValues: map[string]string{"Fix Version": "1.1", "Sprint": "S1"}}},
+			expectedChanged: []TaskDiff{
+				{Key: "EV-1", Changes: []ValueChange{{Field: "Fix Version", From: "1.0", To: "1.1"}}},
+			},
+		},
+		{
+			name: "Keys match case-insensitively",
+			from: []JiraTransitionResult{{Key: "EV-6", Status: "Done"}},
+			to:   []JiraTransitionResult{{Key: "ev-6", Status: "Done"}},
+		},
+		{
+			name: "Changes follow the order of the newer snapshot",
+			from: []JiraTransitionResult{{Key: "EV-1", Status: "To Do"}, {Key: "EV-2", Status: "To Do"}},
+			to:   []JiraTransitionResult{{Key: "EV-2", Status: "Done"}, {Key: "EV-1", Status: "Done"}},
+			expectedChanged: []TaskDiff{
+				{Key: "EV-2", Changes: []ValueChange{{Field: "status", From: "To Do", To: "Done"}}},
+				{Key: "EV-1", Changes: []ValueChange{{Field: "status", From: "To Do", To: "Done"}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := diffEvidence(TransitionCheckResponse{Tasks: tt.from}, TransitionCheckResponse{Tasks: tt.to}, []string{"Done", "Closed"})
+
+			if tt.expectedAdded == nil {
+				tt.expectedAdded = []TaskState{}
+			}
+			if tt.expectedRemoved == nil {
+				tt.expectedRemoved = []TaskState{}
+			}
+			if tt.expectedChanged == nil {
+				tt.expectedChanged = []TaskDiff{}
+			}
+			assert.Equal(t, tt.expectedAdded, diff.Added)
+			assert.Equal(t, tt.expectedRemoved, diff.Removed)
+			assert.Equal(t, tt.expectedChanged, diff.Changed)
+		})
+	}
+}
+
+func TestParseDiffKinds(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         string
+		expected      []string
+		errorContains string
+	}{
+		{name: "Empty never fails", value: "", expected: nil},
+		{name: "Single kind", value: "regressed", expected: []string{DiffRegressed}},
+		{name: "Several kinds", value: "Added, removed", expected: []string{DiffAdded, DiffRemoved}},
+		{name: "Any", value: "any", expected: diffKinds},
+		{name: "Unknown kind", value: "added,moved", errorContains: "expected one of added, removed, changed, regressed or any"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kinds, err := parseDiffKinds(tt.value)
+			if tt.errorContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, kinds)
+		})
+	}
+}
+
+func TestGenerateDiffMarkdown(t *testing.T) {
+	diff := &EvidenceDiff{
+		From:    "qa.json",
+		To:      "prod.json",
+		Added:   []TaskState{{Key: "EV-5", Status: "To Do"}},
+		Removed: []TaskState{{Key: "EV-3", Status: "In Progress"}},
+		Changed: []TaskDiff{
+			{Key: "EV-1", Regressed: true, Changes: []ValueChange{{Field: "status", From: "Done", To: "Reopened"}}},
+			{Key: "EV-2", Changes: []ValueChange{{Field: "assignee", From: "Alice", To: "Bob"}}},
+			{Key: "EV-4", Changes: []ValueChange{{Field: "error", From: "", To: ErrorCodeNotFound}}},
+		},
+	}
+
+	markdown := generateDiffMarkdown(diff)
+	for _, check := range []string{
+		"# JIRA Evidence Diff",
+		"From: qa.json",
+		"Added: 1, removed: 1, changed: 3 (regressed: 1)",
+		"## Added Tickets",
+		"| EV-5 | To Do |",
+		"## Removed Tickets",
+		"| EV-3 | In Progress |",
+		"| EV-1 ⚠️ regressed | status | Done | Reopened |",
+		"| EV-2 | assignee | Alice | Bob |",
+		"| EV-4 | error | _none_ | not_found |",
+	} {
+		assert.Contains(t, markdown, check)
+	}
+
+	assert.Contains(t, generateDiffMarkdown(&EvidenceDiff{}), "No differences in ticket state.")
+}
+
+func TestRunDiffCommand(t *testing.T) {
+	dir := t.TempDir()
+	qa := TransitionCheckResponse{SchemaVersion: CurrentSchemaVersion, Tasks: []JiraTransitionResult{
+		{Key: "EV-1", Status: "Done", Transitions: []Transition{}},
+		{Key: "EV-3", Status: "In Progress", Transitions: []Transition{}},
+	}}
+	prod := TransitionCheckResponse{SchemaVersion: CurrentSchemaVersion, Tasks: []JiraTransitionResult{
+		{Key: "EV-1", Status: "Reopened", Transitions: []Transition{}},
+		{Key: "EV-5", Status: "To Do", Transitions: []Transition{}},
+	}}
+	write := func(name string, response TransitionCheckResponse) string {
+		data, err := json.Marshal(response)
+		require.NoError(t, err)
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, data, 0644))
+		return path
+	}
+	qaFile := write("qa.json", qa)
+	prodFile := write("prod.json", prod)
+	config := &AppConfig{DoneStatuses: []string{"Done"}}
+
+	t.Run("JSON output", func(t *testing.T) {
+		outputFile := filepath.Join(dir, "diff.json")
+		require.NoError(t, runDiffCommand(&FlagConfig{Format: "json", OutputFile: outputFile}, []string{qaFile, prodFile}, config))
+
+		data, err := os.ReadFile(outputFile)
+		require.NoError(t, err)
+		var diff EvidenceDiff
+		require.NoError(t, json.Unmarshal(data, &diff))
+		assert.Equal(t, qaFile, diff.From)
+		assert.Equal(t, prodFile, diff.To)
+		assert.Len(t, diff.Changed, 1)
+	})
+
+	t.Run("Fail on regressions", func(t *testing.T) {
+		outputFile := filepath.Join(dir, "diff.md")
+		err := runDiffCommand(&FlagConfig{FailOn: "regressed,removed", OutputFile: outputFile}, []string{qaFile, prodFile}, config)
+		var exitErr *ExitCodeError
+		require.True(t, errors.As(err, &exitErr))
+		assert.Equal(t, DiffFailureExitCode, exitErr.Code)
+		assert.Equal(t, "evidence differs: 1 regressed, 1 removed", err.Error())
+
+		// Identical snapshots pass
+		assert.NoError(t, runDiffCommand(&FlagConfig{FailOn: "any", OutputFile: outputFile}, []string{qaFile, qaFile}, config))
+	})
+
+	t.Run("Invalid usage", func(t *testing.T) {
+		err := runDiffCommand(&FlagConfig{}, []string{qaFile}, config)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "expected two evidence files")
+
+		err = runDiffCommand(&FlagConfig{Format: "xml"}, []string{qaFile, prodFile}, config)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "expected markdown or json")
+	})
+}
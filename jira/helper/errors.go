@@ -1,6 +1,16 @@
 package main
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
 
 // GitError represents errors from git operations
 type GitError struct {
@@ -12,13 +22,193 @@ func (e *GitError) Error() string {
 	return fmt.Sprintf("git operation '%s' failed: %v", e.Operation, e.Err)
 }
 
-// ValidationError represents validation errors
+// ValidationError represents validation errors; File and Line locate values read from a config file
 type ValidationError struct {
 	Field string
 	Value string
 	Err   error
+	File  string
+	Line  int
 }
 
 func (e *ValidationError) Error() string {
-	return fmt.Sprintf("validation failed for %s='%s': %v", e.Field, e.Value, e.Err)
+	message := fmt.Sprintf("validation failed for %s='%s': %v", e.Field, e.Value, e.Err)
+	if e.File == "" {
+		return message
+	}
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.File, e.Line, message)
+	}
+	return fmt.Sprintf("%s: %s", e.File, message)
+}
+
+// JiraAPIError represents a JIRA API call that failed with an HTTP status
+type JiraAPIError struct {
+	StatusCode int
+	Err        error
+}
+
+func (e *JiraAPIError) Error() string {
+	return fmt.Sprintf("JIRA API returned status %d: %v", e.StatusCode, e.Err)
+}
+
+func (e *JiraAPIError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCodeError requests a specific process exit code from main
+type ExitCodeError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitCodeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitCodeError) Unwrap() error {
+	return e.Err
+}
+
+// Error classes reported in the structured error of a failed task
+const (
+	ErrorCodeUnauthorized     = "unauthorized"
+	ErrorCodePermissionDenied = "permission_denied"
+	ErrorCodeNotFound         = "not_found"
+	ErrorCodeRateLimited      = "rate_limited"
+	ErrorCodeBadRequest       = "bad_request"
+	ErrorCodeServerError      = "server_error"
+	ErrorCodeNetworkError     = "network_error"
+	ErrorCodeUnknown          = "unknown"
+)
+
+// errorCodeWildcard matches every error class without an explicit exit code
+const errorCodeWildcard = "*"
+
+// knownErrorCodes lists the error classes accepted in exit code configuration
+var knownErrorCodes = []string{
+	ErrorCodeUnauthorized,
+	ErrorCodePermissionDenied,
+	ErrorCodeNotFound,
+	ErrorCodeRateLimited,
+	ErrorCodeBadRequest,
+	ErrorCodeServerError,
+	ErrorCodeNetworkError,
+	ErrorCodeUnknown,
+}
+
+// classifyError converts a fetch failure into a structured task error
+func classifyError(err error) *TaskError {
+	if err == nil {
+		return &TaskError{Code: ErrorCodeUnknown, Message: "Could not retrieve issue"}
+	}
+
+	taskErr := &TaskError{Code: ErrorCodeUnknown, Message: err.Error()}
+
+	var apiErr *JiraAPIError
+	if errors.As(err, &apiErr) {
+		taskErr.HTTPStatus = apiErr.StatusCode
+		switch {
+		case apiErr.StatusCode == http.StatusUnauthorized:
+			taskErr.Code = ErrorCodeUnauthorized
+		case apiErr.StatusCode == http.StatusForbidden:
+			taskErr.Code = ErrorCodePermissionDenied
+		case apiErr.StatusCode == http.StatusNotFound:
+			taskErr.Code = ErrorCodeNotFound
+		case apiErr.StatusCode == http.StatusTooManyRequests:
+			taskErr.Code = ErrorCodeRateLimited
+			taskErr.Retryable = true
+		case apiErr.StatusCode >= 500:
+			taskErr.Code = ErrorCodeServerError
+			taskErr.Retryable = true
+		case apiErr.StatusCode >= 400:
+			taskErr.Code = ErrorCodeBadRequest
+		}
+		return taskErr
+	}
+
+	var netErr net.Error
+	var urlErr *url.Error
+	if errors.As(err, &netErr) || errors.As(err, &urlErr) || errors.Is(err, context.DeadlineExceeded) {
+		taskErr.Code = ErrorCodeNetworkError
+		taskErr.Retryable = true
+	}
+
+	return taskErr
+}
+
+// parseErrorExitCodes parses "class=code" pairs such as "unauthorized=3,not_found=0,*=1"
+func parseErrorExitCodes(value string) (map[string]int, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	codes := make(map[string]int)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, codeStr, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("expected class=code, got '%s'", pair)
+		}
+		name = strings.TrimSpace(name)
+		if name != errorCodeWildcard && !isKnownErrorCode(name) {
+			return nil, fmt.Errorf("unknown error class '%s' (known: %s)", name, strings.Join(knownErrorCodes, ", "))
+		}
+		code, err := strconv.Atoi(strings.TrimSpace(codeStr))
+		if err != nil || code < 0 || code > 125 {
+			return nil, fmt.Errorf("invalid exit code '%s' for %s", codeStr, name)
+		}
+		codes[name] = code
+	}
+	return codes, nil
+}
+
+func isKnownErrorCode(name string) bool {
+	for _, code := range knownErrorCodes {
+		if code == name {
+			return true
+		}
+	}
+	return false
+}
+
+// exitCodeForErrors returns an ExitCodeError for the highest exit code configured for the
+// error classes present in the response, or nil when every error is tolerated
+func exitCodeForErrors(response TransitionCheckResponse, exitCodes map[string]int) error {
+	if len(exitCodes) == 0 {
+		return nil
+	}
+
+	exitCode := 0
+	failed := make(map[string][]string)
+	for _, task := range response.Tasks {
+		if task.Error == nil {
+			continue
+		}
+		code, ok := exitCodes[task.Error.Code]
+		if !ok {
+			code = exitCodes[errorCodeWildcard]
+		}
+		if code == 0 {
+			continue
+		}
+		failed[task.Error.Code] = append(failed[task.Error.Code], task.Key)
+		if code > exitCode {
+			exitCode = code
+		}
+	}
+
+	if exitCode == 0 {
+		return nil
+	}
+
+	classes := make([]string, 0, len(failed))
+	for class, keys := range failed {
+		classes = append(classes, fmt.Sprintf("%s (%s)", class, strings.Join(keys, ", ")))
+	}
+	sort.Strings(classes)
+	return &ExitCodeError{Code: exitCode, Err: fmt.Errorf("JIRA fetch errors: %s", strings.Join(classes, "; "))}
 }
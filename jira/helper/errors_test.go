@@ -1,7 +1,10 @@
 package main
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net/url"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -51,6 +54,8 @@ func TestValidationError(t *testing.T) {
 		field         string
 		value         string
 		err           error
+		file          string
+		line          int
 		expectedError string
 	}{
 		{
@@ -81,6 +86,23 @@ func TestValidationError(t *testing.T) {
 			err:           nil,
 			expectedError: "validation failed for test_field='test_value': <nil>",
 		},
+		{
+			name:          "ValidationError from config file",
+			field:         "profiles.cloud.id_regex",
+			value:         "[A-Z",
+			err:           errors.New("error parsing regexp"),
+			file:          ".jira-helper.yaml",
+			line:          7,
+			expectedError: ".jira-helper.yaml:7: validation failed for profiles.cloud.id_regex='[A-Z': error parsing regexp",
+		},
+		{
+			name:          "ValidationError from config file without line",
+			field:         "profile",
+			value:         "missing",
+			err:           errors.New("profile not defined"),
+			file:          ".jira-helper.yaml",
+			expectedError: ".jira-helper.yaml: validation failed for profile='missing': profile not defined",
+		},
 	}
 
 	for _, tt := range tests {
@@ -89,8 +111,218 @@ func TestValidationError(t *testing.T) {
 				Field: tt.field,
 				Value: tt.value,
 				Err:   tt.err,
+				File:  tt.file,
+				Line:  tt.line,
 			}
 			assert.Equal(t, tt.expectedError, validationErr.Error())
 		})
 	}
 }
+
+func TestJiraAPIError(t *testing.T) {
+	inner := errors.New("request failed")
+	apiErr := &JiraAPIError{StatusCode: 404, Err: inner}
+
+	assert.Equal(t, "JIRA API returned status 404: request failed", apiErr.Error())
+	assert.True(t, errors.Is(apiErr, inner))
+}
+
+func TestExitCodeError(t *testing.T) {
+	inner := errors.New("tickets failed")
+	exitErr := &ExitCodeError{Code: 3, Err: fmt.Errorf("wrapped: %w", inner)}
+
+	assert.Equal(t, "wrapped: tickets failed", exitErr.Error())
+	assert.True(t, errors.Is(exitErr, inner))
+
+	var target *ExitCodeError
+	assert.True(t, errors.As(fmt.Errorf("outer: %w", exitErr), &target))
+	assert.Equal(t, 3, target.Code)
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name              string
+		err               error
+		expectedCode      string
+		expectedStatus    int
+		expectedRetryable bool
+	}{
+		{
+			name:         "nil error",
+			err:          nil,
+			expectedCode: ErrorCodeUnknown,
+		},
+		{
+			name:         "plain error",
+			err:          errors.New("something odd"),
+			expectedCode: ErrorCodeUnknown,
+		},
+		{
+			name:           "401 unauthorized",
+			err:            &JiraAPIError{StatusCode: 401, Err: errors.New("failed")},
+			expectedCode:   ErrorCodeUnauthorized,
+			expectedStatus: 401,
+		},
+		{
+			name:           "403 permission denied",
+			err:            &JiraAPIError{StatusCode: 403, Err: errors.New("failed")},
+			expectedCode:   ErrorCodePermissionDenied,
+			expectedStatus: 403,
+		},
+		{
+			name:           "404 not found",
+			err:            &JiraAPIError{StatusCode: 404, Err: errors.New("failed")},
+			expectedCode:   ErrorCodeNotFound,
+			expectedStatus: 404,
+		},
+		{
+			name:              "429 rate limited",
+			err:               &JiraAPIError{StatusCode: 429, Err: errors.New("failed")},
+			expectedCode:      ErrorCodeRateLimited,
+			expectedStatus:    429,
+			expectedRetryable: true,
+		},
+		{
+			name:              "503 server error",
+			err:               &JiraAPIError{StatusCode: 503, Err: errors.New("failed")},
+			expectedCode:      ErrorCodeServerError,
+			expectedStatus:    503,
+			expectedRetryable: true,
+		},
+		{
+			name:           "400 bad request",
+			err:            &JiraAPIError{StatusCode: 400, Err: errors.New("failed")},
+			expectedCode:   ErrorCodeBadRequest,
+			expectedStatus: 400,
+		},
+		{
+			name:              "network error",
+			err:               &url.Error{Op: "Get", URL: "https://example.atlassian.net", Err: errors.New("no such host")},
+			expectedCode:      ErrorCodeNetworkError,
+			expectedRetryable: true,
+		},
+		{
+			name:              "deadline exceeded",
+			err:               fmt.Errorf("fetch: %w", context.DeadlineExceeded),
+			expectedCode:      ErrorCodeNetworkError,
+			expectedRetryable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := classifyError(tt.err)
+			assert.Equal(t, tt.expectedCode, result.Code)
+			assert.Equal(t, tt.expectedStatus, result.HTTPStatus)
+			assert.Equal(t, tt.expectedRetryable, result.Retryable)
+			assert.NotEmpty(t, result.Message)
+		})
+	}
+}
+
+func TestParseErrorExitCodes(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    map[string]int
+		expectError bool
+	}{
+		{
+			name:     "empty value",
+			input:    "",
+			expected: nil,
+		},
+		{
+			name:     "classes and wildcard",
+			input:    "unauthorized=3, permission_denied=3,not_found=0,*=1",
+			expected: map[string]int{"unauthorized": 3, "permission_denied": 3, "not_found": 0, "*": 1},
+		},
+		{
+			name:        "unknown class",
+			input:       "teapot=1",
+			expectError: true,
+		},
+		{
+			name:        "missing code",
+			input:       "not_found",
+			expectError: true,
+		},
+		{
+			name:        "invalid code",
+			input:       "not_found=abc",
+			expectError: true,
+		},
+		{
+			name:        "out of range code",
+			input:       "not_found=300",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseErrorExitCodes(tt.input)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestExitCodeForErrors(t *testing.T) {
+	response := TransitionCheckResponse{
+		Tasks: []JiraTransitionResult{
+			{Key: "EV-1", Status: "Done"},
+			{Key: "EV-2", Status: ErrorStatus, Error: &TaskError{Code: ErrorCodeNotFound}},
+			{Key: "EV-3", Status: ErrorStatus, Error: &TaskError{Code: ErrorCodeUnauthorized}},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		exitCodes    map[string]int
+		expectedCode int
+	}{
+		{
+			name:         "no configuration tolerates everything",
+			exitCodes:    nil,
+			expectedCode: 0,
+		},
+		{
+			name:         "fail on auth, tolerate 404",
+			exitCodes:    map[string]int{"unauthorized": 3, "not_found": 0},
+			expectedCode: 3,
+		},
+		{
+			name:         "highest configured code wins",
+			exitCodes:    map[string]int{"unauthorized": 3, "not_found": 4},
+			expectedCode: 4,
+		},
+		{
+			name:         "wildcard applies to unlisted classes",
+			exitCodes:    map[string]int{"not_found": 0, "*": 1},
+			expectedCode: 1,
+		},
+		{
+			name:         "tolerated classes only",
+			exitCodes:    map[string]int{"not_found": 0, "unauthorized": 0},
+			expectedCode: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := exitCodeForErrors(response, tt.exitCodes)
+			if tt.expectedCode == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			var exitErr *ExitCodeError
+			assert.True(t, errors.As(err, &exitErr))
+			assert.Equal(t, tt.expectedCode, exitErr.Code)
+		})
+	}
+}
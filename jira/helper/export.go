@@ -0,0 +1,377 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Export formats accepted by the export command's --format
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+)
+
+// exportTimeFormat is how CSV exports write timestamps: UTC, in a layout spreadsheets parse as a date
+const exportTimeFormat = "2006-01-02 15:04:05"
+
+// exportSheet is one flattened table of an export: a CSV file, or a worksheet of a workbook
+type exportSheet struct {
+	Name   string
+	Header []string
+	Rows   [][]exportCell
+}
+
+// exportCell is one value of an export; time and number cells stay typed in XLSX workbooks
+type exportCell struct {
+	Text   string
+	Time   *time.Time
+	Number *float64
+}
+
+func textCell(text string) exportCell {
+	return exportCell{Text: text}
+}
+
+// timeCell types a JIRA timestamp as a date, keeping values that cannot be parsed as text
+func timeCell(value string) exportCell {
+	t, err := parseJiraTime(value)
+	if err != nil {
+		return exportCell{Text: value}
+	}
+	t = t.UTC()
+	return exportCell{Time: &t}
+}
+
+func hoursCell(hours *float64) exportCell {
+	return exportCell{Number: hours}
+}
+
+// csvValue formats a cell for CSV. Text that a spreadsheet would evaluate as a formula
+// is prefixed with a quote, so ticket summaries and field values cannot inject formulas.
+func (c exportCell) csvValue() string {
+	switch {
+	case c.Time != nil:
+		return c.Time.Format(exportTimeFormat)
+	case c.Number != nil:
+		return strconv.FormatFloat(*c.Number, 'f', -1, 64)
+	case c.Text != "" && strings.ContainsRune("=+-@\t\r", rune(c.Text[0])):
+		return "'" + c.Text
+	default:
+		return c.Text
+	}
+}
+
+// exportSheets flattens evidence into a tasks sheet, one row per ticket, and a transitions sheet,
+// one row per transition joined to its ticket by key. Tracked field values become extra task columns.
+func exportSheets(response TransitionCheckResponse) []exportSheet {
+	fields := make(map[string]bool)
+	for _, task := range response.Tasks {
+		for field := range task.FieldValues {
+			fields[field] = true
+		}
+	}
+	fieldNames := make([]string, 0, len(fields))
+	for field := range fields {
+		fieldNames = append(fieldNames, field)
+	}
+	sort.Strings(fieldNames)
+
+	tasks := exportSheet{
+		Name: "Tasks",
+		Header: append([]string{"Key", "Link", "Status", "Type", "Project", "Priority", "Assignee", "Reporter",
+			"Created (UTC)", "Updated (UTC)", "Lead Time (hours)", "Cycle Time (hours)", "Error", "Sources"}, fieldNames...),
+	}
+	transitions := exportSheet{
+		Name:   "Transitions",
+		Header: []string{"Key", "From Status", "To Status", "Author", "Author User Name", "Transition Time (UTC)"},
+	}
+
+	for _, task := range response.Tasks {
+		var leadTime, cycleTime *float64
+		if task.Metrics != nil {
+			leadTime = task.Metrics.LeadTimeHours
+			cycleTime = task.Metrics.CycleTimeHours
+		}
+		row := []exportCell{
+			textCell(task.Key),
+			textCell(task.Link),
+			textCell(task.Status),
+			textCell(task.Type),
+			textCell(task.Project),
+			textCell(task.Priority),
+			textCell(stringValue(task.Assignee)),
+			textCell(task.Reporter),
+			timeCell(task.Created),
+			timeCell(task.Updated),
+			hoursCell(leadTime),
+			hoursCell(cycleTime),
+			textCell(errorSummary(task.Error)),
+			textCell(strings.Join(task.Sources, ", ")),
+		}
+		for _, field := range fieldNames {
+			row = append(row, textCell(task.FieldValues[field]))
+		}
+		tasks.Rows = append(tasks.Rows, row)
+
+		for _, transition := range sortedTransitions(task.Transitions) {
+			transitions.Rows = append(transitions.Rows, []exportCell{
+				textCell(task.Key),
+				textCell(transition.FromStatus),
+				textCell(transition.ToStatus),
+				textCell(transition.Author),
+				textCell(transition.AuthorEmail),
+				timeCell(transition.TransitionTime),
+			})
+		}
+	}
+
+	return []exportSheet{tasks, transitions}
+}
+
+// csvExportFiles returns the file of every sheet of a CSV export, e.g. evidence_tasks.csv and evidence_transitions.csv
+func csvExportFiles(outputFile string, sheets []exportSheet) []string {
+	stem := strings.TrimSuffix(outputFile, filepath.Ext(outputFile))
+	files := make([]string, len(sheets))
+	for i, sheet := range sheets {
+		files[i] = fmt.Sprintf("%s_%s.csv", stem, strings.ToLower(sheet.Name))
+	}
+	return files
+}
+
+// writeCSVExport writes every sheet to its own CSV file and returns the files written
+func writeCSVExport(sheets []exportSheet, outputFile string) ([]string, error) {
+	files := csvExportFiles(outputFile, sheets)
+	for i, sheet := range sheets {
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		w.Write(sheet.Header)
+		for _, row := range sheet.Rows {
+			record := make([]string, len(row))
+			for j, cell := range row {
+				record[j] = cell.csvValue()
+			}
+			w.Write(record)
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return nil, fmt.Errorf("error writing CSV: %v", err)
+		}
+		if err := writeToFile(files[i], buf.Bytes()); err != nil {
+			return nil, fmt.Errorf("error writing to file: %v", err)
+		}
+	}
+	return files, nil
+}
+
+// XLSX cell styles, indexes into cellXfs of xlsxStyles
+const (
+	xlsxStyleDate   = 1
+	xlsxStyleHeader = 2
+)
+
+// xlsxStyles defines a date format for time cells and a bold font for header rows
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm:ss"/></numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="3"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
+
+// xlsxPart is one file of the workbook package
+type xlsxPart struct {
+	name    string
+	content string
+}
+
+// xlsxEpoch is day zero of spreadsheet date serial numbers
+var xlsxEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// writeXLSXExport writes the sheets as the worksheets of one Office Open XML workbook
+func writeXLSXExport(sheets []exportSheet, outputFile string) error {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	files := []xlsxPart{
+		{"[Content_Types].xml", xlsxContentTypes(sheets)},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", xlsxWorkbook(sheets)},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels(sheets)},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for i, sheet := range sheets {
+		files = append(files, xlsxPart{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), xlsxWorksheet(sheet)})
+	}
+
+	for _, file := range files {
+		// A fixed timestamp keeps the workbook identical for identical evidence
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC),
+		})
+		if err != nil {
+			return fmt.Errorf("error writing XLSX: %v", err)
+		}
+		if _, err := w.Write([]byte(file.content)); err != nil {
+			return fmt.Errorf("error writing XLSX: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("error writing XLSX: %v", err)
+	}
+
+	if err := writeToFile(outputFile, buf.Bytes()); err != nil {
+		return fmt.Errorf("error writing to file: %v", err)
+	}
+	return nil
+}
+
+func xlsxContentTypes(sheets []exportSheet) string {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	sb.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	sb.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	sb.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	sb.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := range sheets {
+		sb.WriteString(fmt.Sprintf(`<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1))
+	}
+	sb.WriteString(`</Types>`)
+	return sb.String()
+}
+
+func xlsxWorkbook(sheets []exportSheet) string {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, sheet := range sheets {
+		sb.WriteString(fmt.Sprintf(`<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlEscape(sheet.Name), i+1, i+1))
+	}
+	sb.WriteString(`</sheets></workbook>`)
+	return sb.String()
+}
+
+func xlsxWorkbookRels(sheets []exportSheet) string {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := range sheets {
+		sb.WriteString(fmt.Sprintf(`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1))
+	}
+	// The styles relationship follows the worksheets, so sheet IDs match their position
+	sb.WriteString(fmt.Sprintf(`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(sheets)+1))
+	sb.WriteString(`</Relationships>`)
+	return sb.String()
+}
+
+// xlsxWorksheet renders a sheet with a frozen, filterable header row
+func xlsxWorksheet(sheet exportSheet) string {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	sb.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	sb.WriteString(`<sheetData>`)
+
+	sb.WriteString(`<row r="1">`)
+	for col, name := range sheet.Header {
+		sb.WriteString(fmt.Sprintf(`<c r="%s1" s="%d" t="inlineStr"><is><t>%s</t></is></c>`, xlsxColumn(col), xlsxStyleHeader, xmlEscape(name)))
+	}
+	sb.WriteString(`</row>`)
+
+	for i, row := range sheet.Rows {
+		r := i + 2
+		sb.WriteString(fmt.Sprintf(`<row r="%d">`, r))
+		for col, cell := range row {
+			ref := fmt.Sprintf("%s%d", xlsxColumn(col), r)
+			switch {
+			case cell.Time != nil:
+				serial := float64(cell.Time.Sub(xlsxEpoch)) / float64(24*time.Hour)
+				sb.WriteString(fmt.Sprintf(`<c r="%s" s="%d"><v>%s</v></c>`, ref, xlsxStyleDate, strconv.FormatFloat(serial, 'f', -1, 64)))
+			case cell.Number != nil:
+				sb.WriteString(fmt.Sprintf(`<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(*cell.Number, 'f', -1, 64)))
+			case cell.Text != "":
+				sb.WriteString(fmt.Sprintf(`<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, xmlEscape(cell.Text)))
+			}
+		}
+		sb.WriteString(`</row>`)
+	}
+
+	sb.WriteString(`</sheetData>`)
+	if len(sheet.Header) > 0 {
+		sb.WriteString(fmt.Sprintf(`<autoFilter ref="A1:%s%d"/>`, xlsxColumn(len(sheet.Header)-1), len(sheet.Rows)+1))
+	}
+	sb.WriteString(`</worksheet>`)
+	return sb.String()
+}
+
+// xlsxColumn returns the column letters of a zero-based column index: A, B, ..., Z, AA, ...
+func xlsxColumn(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+func xmlEscape(text string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(text))
+	return sb.String()
+}
+
+// runExportCommand flattens an evidence file into CSV files or an XLSX workbook for auditors
+func runExportCommand(flags *FlagConfig, args []string, config *AppConfig) error {
+	format := strings.ToLower(getOrDefault(flags.Format, ExportFormatCSV))
+	if format != ExportFormatCSV && format != ExportFormatXLSX {
+		return &ValidationError{Field: "format", Value: flags.Format, Err: fmt.Errorf("expected csv or xlsx")}
+	}
+	inputFile := resolveInputFile(flags, config)
+	outputFile := getOrDefault(flags.ExportOutput, strings.TrimSuffix(config.evidenceFile(), filepath.Ext(config.evidenceFile()))+"."+format)
+
+	fmt.Println("=== JIRA Evidence Export ===")
+	fmt.Printf("Input JSON file: %s\n", inputFile)
+
+	response, _, err := loadTransitionResponse(inputFile)
+	if err != nil {
+		return err
+	}
+	response.Tasks = sortedTasks(response.Tasks, config.TaskOrder)
+	sheets := exportSheets(response)
+	fmt.Printf("Exporting %d tasks and %d transitions\n", len(sheets[0].Rows), len(sheets[1].Rows))
+
+	if format == ExportFormatXLSX {
+		if err := writeXLSXExport(sheets, outputFile); err != nil {
+			return err
+		}
+		fmt.Printf("Workbook saved to: %s\n", outputFile)
+		return nil
+	}
+
+	files, err := writeCSVExport(sheets, outputFile)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		fmt.Printf("CSV saved to: %s\n", file)
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportCellCSVValue(t *testing.T) {
+	hours := -1.5
+	tests := []struct {
+		name     string
+		cell     exportCell
+		expected string
+	}{
+		{name: "Plain text", cell: exportCell{Text: "Fix report encoding"}, expected: "Fix report encoding"},
+		{name: "Empty text", cell: exportCell{}, expected: ""},
+		{name: "Formula", cell: exportCell{Text: "=HYPERLINK(\"https://example.com\")"}, expected: "'=HYPERLINK(\"https://example.com\")"},
+		{name: "Plus sign", cell: exportCell{Text: "+1 for this"}, expected: "'+1 for this"},
+		{name: "Minus sign", cell: exportCell{Text: "-2+3"}, expected: "'-2+3"},
+		{name: "At sign", cell: exportCell{Text: "@SUM(A1)"}, expected: "'@SUM(A1)"},
+		{name: "Leading tab", cell: exportCell{Text: "\t=1+1"}, expected: "'\t=1+1"},
+		{name: "Leading carriage return", cell: exportCell{Text: "\r=1+1"}, expected: "'\r=1+1"},
+		{name: "Sign inside text", cell: exportCell{Text: "a=b"}, expected: "a=b"},
+		{name: "Negative number is not text", cell: exportCell{Number: &hours}, expected: "-1.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.cell.csvValue())
+		})
+	}
+}
+
+func TestExportSheets(t *testing.T) {
+	lead := 23.5
+	response := TransitionCheckResponse{
+		SchemaVersion: CurrentSchemaVersion,
+		Tasks: []JiraTransitionResult{
+			{
+				Key:         "EV-1",
+				Status:      "Done",
+				Created:     "2025-01-01T10:00:00.000+0200",
+				Sources:     []string{"btcwallet", "btcwallet-ui"},
+				FieldValues: map[string]string{"Fix Version": "1.4"},
+				Transitions: []Transition{
+					{FromStatus: "In Progress", ToStatus: "Done", TransitionTime: "2025-01-02T09:30:00.000+0000"},
+					{FromStatus: "To Do", ToStatus: "In Progress", TransitionTime: "2025-01-01T12:00:00.000+0000"},
+				},
+				Metrics: &TaskMetrics{LeadTimeHours: &lead},
+			},
+			{Key: "EV-2", Status: ErrorStatus, Type: ErrorType, Transitions: []Transition{},
+				Error: &TaskError{Code: ErrorCodeNotFound, HTTPStatus: 404}},
+		},
+	}
+	sheets := exportSheets(response)
+	require.Len(t, sheets, 2)
+
+	tasks := sheets[0]
+	assert.Equal(t, "Tasks", tasks.Name)
+	assert.Equal(t, "Fix Version", tasks.Header[len(tasks.Header)-1])
+	require.Len(t, tasks.Rows, 2)
+
+	ev1 := tasks.Rows[0]
+	require.NotNil(t, ev1[8].Time)
+	assert.Equal(t, "2025-01-01 08:00:00", ev1[8].csvValue(), "created is converted to UTC")
+	require.NotNil(t, ev1[10].Number)
+	assert.Equal(t, 23.5, *ev1[10].Number)
+	assert.Nil(t, ev1[11].Number)
+	assert.Equal(t, "btcwallet, btcwallet-ui", ev1[13].Text)
+	assert.Equal(t, "1.4", ev1[14].Text)
+
+	ev2 := tasks.Rows[1]
+	assert.Nil(t, ev2[8].Time)
+	assert.Equal(t, "not_found (HTTP 404)", ev2[12].Text)
+
+	// One row per transition, in transition order, joined by key
+	transitions := sheets[1]
+	assert.Equal(t, "Transitions", transitions.Name)
+	require.Len(t, transitions.Rows, 2)
+	assert.Equal(t, "EV-1", transitions.Rows[0][0].Text)
+	assert.Equal(t, "In Progress", transitions.Rows[0][2].Text)
+	assert.Equal(t, "2025-01-01 12:00:00", transitions.Rows[0][5].csvValue())
+	assert.Equal(t, "Done", transitions.Rows[1][2].Text)
+}
+
+func TestXLSXColumn(t *testing.T) {
+	for index, expected := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		assert.Equal(t, expected, xlsxColumn(index))
+	}
+}
+
+func TestWriteCSVExport(t *testing.T) {
+	lead := 23.5
+	response := TransitionCheckResponse{Tasks: []JiraTransitionResult{
+		{
+			Key:         "EV-1",
+			Status:      "Done",
+			Type:        "Task",
+			Created:     "2025-01-01T10:00:00.000+0200",
+			Updated:     "2025-01-02T09:30:00.000+0000",
+			Assignee:    strPtr("John Doe"),
+			Reporter:    "Jane, Smith",
+			FieldValues: map[string]string{"Fix Version": "=1+1"},
+			Sources:     []string{"btcwallet", "btcwallet-ui"},
+			Transitions: []Transition{
+				{FromStatus: "In Progress", ToStatus: "Done", Author: "John Doe", AuthorEmail: "jdoe", TransitionTime: "2025-01-02T09:30:00.000+0000"},
+				{FromStatus: "To Do", ToStatus: "In Progress", Author: "John Doe", AuthorEmail: "jdoe", TransitionTime: "2025-01-01T12:00:00.000+0000"},
+			},
+			Metrics: &TaskMetrics{LeadTimeHours: &lead},
+		},
+	}}
+	dir := t.TempDir()
+	files, err := writeCSVExport(exportSheets(response), filepath.Join(dir, "release.csv"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "release_tasks.csv"), filepath.Join(dir, "release_transitions.csv")}, files)
+
+	read := func(path string) [][]string {
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		defer f.Close()
+		records, err := csv.NewReader(f).ReadAll()
+		require.NoError(t, err)
+		return records
+	}
+
+	tasks := read(files[0])
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "Key", tasks[0][0])
+	assert.Equal(t, []string{"EV-1", "", "Done", "Task", "", "", "John Doe", "Jane, Smith",
+		"2025-01-01 08:00:00", "2025-01-02 09:30:00", "23.5", "", "", "btcwallet, btcwallet-ui", "'=1+1"}, tasks[1])
+
+	transitions := read(files[1])
+	require.Len(t, transitions, 3)
+	assert.Equal(t, []string{"EV-1", "To Do", "In Progress", "John Doe", "jdoe", "2025-01-01 12:00:00"}, transitions[1])
+}
+
+func TestWriteXLSXExport(t *testing.T) {
+	lead := 23.5
+	response := TransitionCheckResponse{Tasks: []JiraTransitionResult{
+		{
+			Key:         "EV-1",
+			Created:     "2025-01-01T10:00:00.000+0200",
+			Reporter:    "Jane, Smith",
+			FieldValues: map[string]string{"Fix Version": "=1+1"},
+			Transitions: []Transition{
+				{FromStatus: "In Progress", ToStatus: "Done", TransitionTime: "2025-01-02T09:30:00.000+0000"},
+				{FromStatus: "To Do", ToStatus: "In Progress", TransitionTime: "2025-01-01T12:00:00.000+0000"},
+			},
+			Metrics: &TaskMetrics{LeadTimeHours: &lead},
+		},
+		{Key: "EV-2", Status: ErrorStatus, Transitions: []Transition{}},
+	}}
+	outputFile := filepath.Join(t.TempDir(), "release.xlsx")
+	require.NoError(t, writeXLSXExport(exportSheets(response), outputFile))
+
+	zr, err := zip.OpenReader(outputFile)
+	require.NoError(t, err)
+	defer zr.Close()
+
+	parts := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		require.NoError(t, err)
+		parts[f.Name] = string(data)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels",
+		"xl/styles.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		assert.Contains(t, parts, name)
+	}
+	assert.Contains(t, parts["xl/workbook.xml"], `<sheet name="Tasks" sheetId="1" r:id="rId1"/>`)
+	assert.Contains(t, parts["xl/workbook.xml"], `<sheet name="Transitions" sheetId="2" r:id="rId2"/>`)
+
+	tasks := parts["xl/worksheets/sheet1.xml"]
+	// 2025-01-01 08:00 UTC is day 45658 of the spreadsheet epoch, a third of a day in
+	assert.Contains(t, tasks, `<c r="I2" s="1"><v>45658.333333333336</v></c>`)
+	assert.Contains(t, tasks, `<c r="K2"><v>23.5</v></c>`)
+	assert.Contains(t, tasks, `<c r="H2" t="inlineStr"><is><t xml:space="preserve">Jane, Smith</t></is></c>`)
+	// Workbook cells are typed, so text is never evaluated and is written as is
+	assert.Contains(t, tasks, `<c r="O2" t="inlineStr"><is><t xml:space="preserve">=1+1</t></is></c>`)
+	assert.Contains(t, tasks, `<autoFilter ref="A1:O3"/>`)
+	assert.Contains(t, parts["xl/worksheets/sheet2.xml"], `<c r="A3" t="inlineStr"><is><t xml:space="preserve">EV-1</t></is></c>`)
+
+	t.Run("Identical evidence writes an identical workbook", func(t *testing.T) {
+		again := filepath.Join(t.TempDir(), "again.xlsx")
+		require.NoError(t, writeXLSXExport(exportSheets(response), again))
+		first, err := os.ReadFile(outputFile)
+		require.NoError(t, err)
+		second, err := os.ReadFile(again)
+		require.NoError(t, err)
+		assert.Equal(t, first, second)
+	})
+}
+
+func TestRunExportCommand(t *testing.T) {
+	dir := t.TempDir()
+	inputFile := filepath.Join(dir, "evidence.json")
+	require.NoError(t, os.WriteFile(inputFile, []byte(`{"tasks": [{"key": "EV-1", "status": "Done", "transitions": []}]}`), 0644))
+
+	t.Run("CSV", func(t *testing.T) {
+		err := runExportCommand(&FlagConfig{InputFile: inputFile, ExportOutput: filepath.Join(dir, "audit.csv")}, nil, &AppConfig{})
+		require.NoError(t, err)
+		assert.FileExists(t, filepath.Join(dir, "audit_tasks.csv"))
+		assert.FileExists(t, filepath.Join(dir, "audit_transitions.csv"))
+	})
+
+	t.Run("XLSX", func(t *testing.T) {
+		outputFile := filepath.Join(dir, "audit.xlsx")
+		err := runExportCommand(&FlagConfig{InputFile: inputFile, ExportOutput: outputFile, Format: "XLSX"}, nil, &AppConfig{})
+		require.NoError(t, err)
+		assert.FileExists(t, outputFile)
+	})
+
+	t.Run("Unknown format", func(t *testing.T) {
+		err := runExportCommand(&FlagConfig{InputFile: inputFile, Format: "ods"}, nil, &AppConfig{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "expected csv or xlsx")
+	})
+}
@@ -0,0 +1,485 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Constants for the fake JIRA server
+const (
+	// FakeJiraAPIPrefix is the REST API path served by the fake
+	FakeJiraAPIPrefix = "/rest/api/2/"
+	// DefaultFakeJiraAddr is where serve-fake listens by default
+	DefaultFakeJiraAddr = "127.0.0.1:8089"
+	// DefaultFakeJiraPageSize caps maxResults on paginated endpoints, like JIRA's own default
+	DefaultFakeJiraPageSize = 50
+)
+
+// embeddedFakeJiraFixtures are served when no fixture directory is given
+//
+//go:embed fixtures/fakejira
+var embeddedFakeJiraFixtures embed.FS
+
+// defaultFakeJiraFixtures returns the built-in fixtures (EV-1 to EV-3, plus EV-401 and EV-429 faults)
+func defaultFakeJiraFixtures() fs.FS {
+	fixtures, err := fs.Sub(embeddedFakeJiraFixtures, "fixtures/fakejira")
+	if err != nil {
+		panic(err) // The embedded directory is fixed at build time
+	}
+	return fixtures
+}
+
+// FakeFault makes the fake answer with an error status instead of the fixture
+type FakeFault struct {
+	Status int `json:"status"`
+	// RetryAfter is sent as the Retry-After header (seconds), typically with 429
+	RetryAfter int `json:"retry_after,omitempty"`
+	// Times limits how many requests fail before the fixture is served; 0 fails every request
+	Times int `json:"times,omitempty"`
+}
+
+// FakeJira serves a subset of the JIRA Cloud REST API from fixture files, so tests and demos run without a
+// live instance. It serves issues (with expand=changelog), changelogs, comments, search and fields.
+//
+// Fixture layout:
+//   - issues/<KEY>.json: an issue as JIRA returns it with expand=changelog; comments live under fields.comment
+//   - fields.json: the response of /field
+//   - faults.json (optional): issue keys, "search" or "field" mapped to a FakeFault
+type FakeJira struct {
+	// Username and Token, when set, are the only basic auth credentials accepted; anything else gets 401
+	Username string
+	Token    string
+	// PageSize caps maxResults on paginated endpoints (default: DefaultFakeJiraPageSize)
+	PageSize int
+
+	fixtures fs.FS
+	faults   map[string]FakeFault
+
+	mu       sync.Mutex
+	hits     map[string]int
+	requests []string
+}
+
+// NewFakeJira creates a fake JIRA serving the given fixtures
+func NewFakeJira(fixtures fs.FS) (*FakeJira, error) {
+	if info, err := fs.Stat(fixtures, "issues"); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("fixtures have no issues directory")
+	}
+
+	faults := make(map[string]FakeFault)
+	data, err := fs.ReadFile(fixtures, "faults.json")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read faults.json: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &faults); err != nil {
+			return nil, fmt.Errorf("failed to parse faults.json: %w", err)
+		}
+	}
+
+	return &FakeJira{
+		fixtures: fixtures,
+		faults:   faults,
+		hits:     make(map[string]int),
+	}, nil
+}
+
+// Requests returns the method, path and query of every request received so far
+func (f *FakeJira) Requests() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.requests...)
+}
+
+// ServeHTTP routes a request to the fixture it asks for
+func (f *FakeJira) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.RequestURI())
+	f.mu.Unlock()
+
+	if r.Method != http.MethodGet {
+		writeFakeError(w, http.StatusMethodNotAllowed, "The fake JIRA only serves GET requests.")
+		return
+	}
+	if !f.authorized(r) {
+		writeFakeError(w, http.StatusUnauthorized, "Client must be authenticated to access this resource.")
+		return
+	}
+
+	route := strings.TrimPrefix(r.URL.Path, FakeJiraAPIPrefix)
+	if route == r.URL.Path {
+		writeFakeError(w, http.StatusNotFound, "No fake resource at "+r.URL.Path)
+		return
+	}
+
+	switch {
+	case route == "field":
+		if !f.fault(w, "field") {
+			f.serveFields(w)
+		}
+	case route == "search":
+		if !f.fault(w, "search") {
+			f.serveSearch(w, r)
+		}
+	case strings.HasPrefix(route, "issue/"):
+		parts := strings.Split(strings.TrimPrefix(route, "issue/"), "/")
+		key := strings.ToUpper(parts[0])
+		if f.fault(w, key) {
+			return
+		}
+		issue, err := f.loadIssue(key)
+		if err != nil {
+			writeFakeLoadError(w, err)
+			return
+		}
+		switch {
+		case len(parts) == 1:
+			f.serveIssue(w, r, issue)
+		case len(parts) == 2 && parts[1] == "changelog":
+			f.serveChangelog(w, r, issue)
+		case len(parts) == 2 && parts[1] == "comment":
+			f.serveComments(w, r, issue)
+		default:
+			writeFakeError(w, http.StatusNotFound, "No fake resource at "+r.URL.Path)
+		}
+	default:
+		writeFakeError(w, http.StatusNotFound, "No fake resource at "+r.URL.Path)
+	}
+}
+
+// authorized checks basic auth against the configured credentials
+func (f *FakeJira) authorized(r *http.Request) bool {
+	if f.Username == "" && f.Token == "" {
+		return true
+	}
+	username, token, ok := r.BasicAuth()
+	return ok && username == f.Username && token == f.Token
+}
+
+// fault writes the simulated failure configured for name, if it still applies
+func (f *FakeJira) fault(w http.ResponseWriter, name string) bool {
+	fault, ok := f.faults[name]
+	if !ok {
+		return false
+	}
+
+	f.mu.Lock()
+	f.hits[name]++
+	hits := f.hits[name]
+	f.mu.Unlock()
+	if fault.Times > 0 && hits > fault.Times {
+		return false
+	}
+
+	if fault.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(fault.RetryAfter))
+	}
+	writeFakeError(w, fault.Status, fmt.Sprintf("Simulated %d %s", fault.Status, http.StatusText(fault.Status)))
+	return true
+}
+
+func (f *FakeJira) serveFields(w http.ResponseWriter) {
+	data, err := fs.ReadFile(f.fixtures, "fields.json")
+	if err != nil {
+		writeFakeLoadError(w, err)
+		return
+	}
+	var fields []interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		writeFakeError(w, http.StatusInternalServerError, "Invalid fields.json: "+err.Error())
+		return
+	}
+	writeFakeJSON(w, fields)
+}
+
+// serveIssue serves an issue; the changelog is only included with expand=changelog, as in JIRA
+func (f *FakeJira) serveIssue(w http.ResponseWriter, r *http.Request, issue map[string]interface{}) {
+	writeFakeJSON(w, expandIssue(issue, r.URL.Query().Get("expand")))
+}
+
+func (f *FakeJira) serveChangelog(w http.ResponseWriter, r *http.Request, issue map[string]interface{}) {
+	histories := issueHistories(issue)
+	startAt, maxResults, err := f.pageParams(r)
+	if err != nil {
+		writeFakeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	values := pageOf(histories, startAt, maxResults)
+	writeFakeJSON(w, map[string]interface{}{
+		"startAt":    startAt,
+		"maxResults": maxResults,
+		"total":      len(histories),
+		"isLast":     startAt+len(values) >= len(histories),
+		"values":     values,
+	})
+}
+
+func (f *FakeJira) serveComments(w http.ResponseWriter, r *http.Request, issue map[string]interface{}) {
+	var comments []interface{}
+	if fields, ok := issue["fields"].(map[string]interface{}); ok {
+		if comment, ok := fields["comment"].(map[string]interface{}); ok {
+			comments, _ = comment["comments"].([]interface{})
+		}
+	}
+
+	startAt, maxResults, err := f.pageParams(r)
+	if err != nil {
+		writeFakeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeFakeJSON(w, map[string]interface{}{
+		"startAt":    startAt,
+		"maxResults": maxResults,
+		"total":      len(comments),
+		"comments":   pageOf(comments, startAt, maxResults),
+	})
+}
+
+// fakeJQLPatterns are the JQL forms the fake understands; ORDER BY clauses are accepted and ignored
+var fakeJQLPatterns = struct {
+	orderBy, keyEquals, keyIn, project *regexp.Regexp
+}{
+	orderBy:   regexp.MustCompile(`(?i)\s*order\s+by\s+.*$`),
+	keyEquals: regexp.MustCompile(`(?i)^key\s*=\s*"?([A-Za-z0-9_]+-[0-9]+)"?$`),
+	keyIn:     regexp.MustCompile(`(?i)^key\s+in\s*\(([^)]*)\)$`),
+	project:   regexp.MustCompile(`(?i)^project\s*=\s*"?([A-Za-z0-9_]+)"?$`),
+}
+
+// serveSearch serves /search for empty JQL, "key = X", "key in (X, Y)" and "project = P", in key order
+func (f *FakeJira) serveSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	match, err := fakeJQLMatcher(query.Get("jql"))
+	if err != nil {
+		writeFakeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	startAt, maxResults, err := f.pageParams(r)
+	if err != nil {
+		writeFakeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	keys, err := f.issueKeys()
+	if err != nil {
+		writeFakeLoadError(w, err)
+		return
+	}
+	var issues []interface{}
+	for _, key := range keys {
+		if !match(key) {
+			continue
+		}
+		issue, err := f.loadIssue(key)
+		if err != nil {
+			writeFakeLoadError(w, err)
+			return
+		}
+		issues = append(issues, expandIssue(issue, query.Get("expand")))
+	}
+
+	writeFakeJSON(w, map[string]interface{}{
+		"startAt":    startAt,
+		"maxResults": maxResults,
+		"total":      len(issues),
+		"issues":     pageOf(issues, startAt, maxResults),
+	})
+}
+
+// fakeJQLMatcher turns the supported JQL subset into a key filter
+func fakeJQLMatcher(jql string) (func(key string) bool, error) {
+	jql = strings.TrimSpace(fakeJQLPatterns.orderBy.ReplaceAllString(jql, ""))
+	if jql == "" {
+		return func(string) bool { return true }, nil
+	}
+
+	if match := fakeJQLPatterns.keyEquals.FindStringSubmatch(jql); match != nil {
+		want := strings.ToUpper(match[1])
+		return func(key string) bool { return key == want }, nil
+	}
+	if match := fakeJQLPatterns.keyIn.FindStringSubmatch(jql); match != nil {
+		want := make(map[string]bool)
+		for _, key := range strings.Split(match[1], ",") {
+			want[strings.ToUpper(strings.Trim(strings.TrimSpace(key), `"`))] = true
+		}
+		return func(key string) bool { return want[key] }, nil
+	}
+	if match := fakeJQLPatterns.project.FindStringSubmatch(jql); match != nil {
+		prefix := strings.ToUpper(match[1]) + "-"
+		return func(key string) bool { return strings.HasPrefix(key, prefix) }, nil
+	}
+	return nil, fmt.Errorf("unsupported JQL for the fake JIRA: %s", jql)
+}
+
+// pageParams reads startAt and maxResults, capping maxResults at the page size
+func (f *FakeJira) pageParams(r *http.Request) (int, int, error) {
+	pageSize := f.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultFakeJiraPageSize
+	}
+
+	query := r.URL.Query()
+	startAt, maxResults := 0, pageSize
+	if value := query.Get("startAt"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return 0, 0, fmt.Errorf("invalid startAt '%s'", value)
+		}
+		startAt = parsed
+	}
+	if value := query.Get("maxResults"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return 0, 0, fmt.Errorf("invalid maxResults '%s'", value)
+		}
+		if parsed < pageSize {
+			maxResults = parsed
+		}
+	}
+	return startAt, maxResults, nil
+}
+
+// pageOf returns the items on the requested page, never nil so it encodes as []
+func pageOf(items []interface{}, startAt, maxResults int) []interface{} {
+	if startAt >= len(items) {
+		return []interface{}{}
+	}
+	end := startAt + maxResults
+	if end > len(items) {
+		end = len(items)
+	}
+	return append([]interface{}{}, items[startAt:end]...)
+}
+
+// loadIssue reads issues/<key>.json; the key defaults to the file name
+func (f *FakeJira) loadIssue(key string) (map[string]interface{}, error) {
+	data, err := fs.ReadFile(f.fixtures, path.Join("issues", key+".json"))
+	if err != nil {
+		return nil, err
+	}
+	var issue map[string]interface{}
+	if err := json.Unmarshal(data, &issue); err != nil {
+		return nil, fmt.Errorf("invalid fixture for %s: %w", key, err)
+	}
+	if _, ok := issue["key"]; !ok {
+		issue["key"] = key
+	}
+	return issue, nil
+}
+
+// issueKeys lists the fixture issue keys sorted by project, then number
+func (f *FakeJira) issueKeys() ([]string, error) {
+	entries, err := fs.ReadDir(f.fixtures, "issues")
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			keys = append(keys, strings.ToUpper(strings.TrimSuffix(entry.Name(), ".json")))
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		projectI, numberI, _ := strings.Cut(keys[i], "-")
+		projectJ, numberJ, _ := strings.Cut(keys[j], "-")
+		if projectI != projectJ {
+			return projectI < projectJ
+		}
+		if len(numberI) != len(numberJ) {
+			return len(numberI) < len(numberJ)
+		}
+		return numberI < numberJ
+	})
+	return keys, nil
+}
+
+// expandIssue returns the issue with its changelog only when expand asks for it
+func expandIssue(issue map[string]interface{}, expand string) map[string]interface{} {
+	expanded := make(map[string]interface{}, len(issue))
+	for key, value := range issue {
+		expanded[key] = value
+	}
+	delete(expanded, "changelog")
+
+	for _, item := range strings.Split(expand, ",") {
+		if strings.TrimSpace(item) == "changelog" {
+			histories := issueHistories(issue)
+			expanded["changelog"] = map[string]interface{}{
+				"startAt":    0,
+				"maxResults": len(histories),
+				"total":      len(histories),
+				"histories":  histories,
+			}
+		}
+	}
+	return expanded
+}
+
+// issueHistories returns the changelog histories of a fixture issue
+func issueHistories(issue map[string]interface{}) []interface{} {
+	changelog, _ := issue["changelog"].(map[string]interface{})
+	histories, _ := changelog["histories"].([]interface{})
+	if histories == nil {
+		return []interface{}{}
+	}
+	return histories
+}
+
+func writeFakeJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeFakeError answers in JIRA's error format
+func writeFakeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{"errorMessages": []string{message}, "errors": map[string]string{}})
+}
+
+// writeFakeLoadError answers 404 for missing fixtures, as JIRA does for unknown issues, and 500 otherwise
+func writeFakeLoadError(w http.ResponseWriter, err error) {
+	if errors.Is(err, fs.ErrNotExist) {
+		writeFakeError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+	writeFakeError(w, http.StatusInternalServerError, err.Error())
+}
+
+// runServeFakeMode serves the fake JIRA until the process is stopped
+func runServeFakeMode(flags *FlagConfig) error {
+	fixtures, source := defaultFakeJiraFixtures(), "built-in fixtures"
+	if flags.Fixtures != "" {
+		fixtures, source = os.DirFS(flags.Fixtures), flags.Fixtures
+	}
+
+	fake, err := NewFakeJira(fixtures)
+	if err != nil {
+		return fmt.Errorf("failed to load fake JIRA fixtures from %s: %w", source, err)
+	}
+	fake.Username = flags.FakeUsername
+	fake.Token = flags.FakeToken
+	fake.PageSize = flags.PageSize
+
+	listener, err := net.Listen("tcp", getOrDefault(flags.Addr, DefaultFakeJiraAddr))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("=== Fake JIRA ===")
+	fmt.Printf("Serving %s at http://%s\n", source, listener.Addr())
+	fmt.Printf("Point the helper at it with JIRA_URL=http://%s\n", listener.Addr())
+	return http.Serve(listener, fake)
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeJiraServer starts the fake JIRA with the built-in fixtures behind an httptest server
+func newFakeJiraServer(t *testing.T, username, token string) (*FakeJira, *httptest.Server) {
+	t.Helper()
+	fake, err := NewFakeJira(defaultFakeJiraFixtures())
+	require.NoError(t, err)
+	fake.Username = username
+	fake.Token = token
+
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+// getFakeJSON requests path from the server and decodes the JSON body
+func getFakeJSON(t *testing.T, server *httptest.Server, path string) (int, map[string]interface{}) {
+	t.Helper()
+	resp, err := http.Get(server.URL + path)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return resp.StatusCode, body
+}
+
+func TestFakeJiraIssue(t *testing.T) {
+	_, server := newFakeJiraServer(t, "", "")
+
+	status, issue := getFakeJSON(t, server, "/rest/api/2/issue/EV-1")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "EV-1", issue["key"])
+	assert.NotContains(t, issue, "changelog", "changelog is only returned when expanded")
+
+	status, issue = getFakeJSON(t, server, "/rest/api/2/issue/ev-1?expand=renderedFields,changelog")
+	assert.Equal(t, http.StatusOK, status)
+	changelog := issue["changelog"].(map[string]interface{})
+	assert.Equal(t, float64(3), changelog["total"])
+	assert.Len(t, changelog["histories"], 3)
+
+	status, body := getFakeJSON(t, server, "/rest/api/2/issue/EV-404")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, []interface{}{"Issue does not exist or you do not have permission to see it."}, body["errorMessages"])
+}
+
+func TestFakeJiraPagination(t *testing.T) {
+	fake, server := newFakeJiraServer(t, "", "")
+	fake.PageSize = 2
+
+	tests := []struct {
+		name          string
+		path          string
+		itemsKey      string
+		expectedTotal int
+		expectedItems int
+		expectedLast  interface{}
+	}{
+		{name: "Changelog first page", path: "/rest/api/2/issue/EV-1/changelog", itemsKey: "values", expectedTotal: 3, expectedItems: 2, expectedLast: false},
+		{name: "Changelog last page", path: "/rest/api/2/issue/EV-1/changelog?startAt=2", itemsKey: "values", expectedTotal: 3, expectedItems: 1, expectedLast: true},
+		{name: "Changelog past the end", path: "/rest/api/2/issue/EV-1/changelog?startAt=10", itemsKey: "values", expectedTotal: 3, expectedItems: 0, expectedLast: true},
+		{name: "Comments capped at page size", path: "/rest/api/2/issue/EV-1/comment?maxResults=100", itemsKey: "comments", expectedTotal: 3, expectedItems: 2},
+		{name: "Comments smaller page", path: "/rest/api/2/issue/EV-1/comment?startAt=1&maxResults=1", itemsKey: "comments", expectedTotal: 3, expectedItems: 1},
+		{name: "Search all", path: "/rest/api/2/search", itemsKey: "issues", expectedTotal: 3, expectedItems: 2},
+		{name: "Search second page", path: "/rest/api/2/search?startAt=2", itemsKey: "issues", expectedTotal: 3, expectedItems: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := getFakeJSON(t, server, tt.path)
+			require.Equal(t, http.StatusOK, status)
+			assert.Equal(t, float64(tt.expectedTotal), body["total"])
+			assert.Len(t, body[tt.itemsKey], tt.expectedItems)
+			if tt.expectedLast != nil {
+				assert.Equal(t, tt.expectedLast, body["isLast"])
+			}
+		})
+	}
+
+	status, _ := getFakeJSON(t, server, "/rest/api/2/issue/EV-1/changelog?startAt=-1")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestFakeJiraSearch(t *testing.T) {
+	_, server := newFakeJiraServer(t, "", "")
+
+	tests := []struct {
+		name           string
+		jql            string
+		expectedStatus int
+		expectedKeys   []string
+	}{
+		{name: "Key equals", jql: "key = EV-2", expectedStatus: http.StatusOK, expectedKeys: []string{"EV-2"}},
+		{name: "Key in list", jql: `key in (EV-3, "ev-1", EV-99) ORDER BY key`, expectedStatus: http.StatusOK, expectedKeys: []string{"EV-1", "EV-3"}},
+		{name: "Project", jql: "project = EV", expectedStatus: http.StatusOK, expectedKeys: []string{"EV-1", "EV-2", "EV-3"}},
+		{name: "Other project", jql: "project = OPS", expectedStatus: http.StatusOK, expectedKeys: []string{}},
+		{name: "Unsupported JQL", jql: "status = Done", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/rest/api/2/search", nil)
+			require.NoError(t, err)
+			query := req.URL.Query()
+			query.Set("jql", tt.jql)
+			req.URL.RawQuery = query.Encode()
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Issues []struct {
+					Key string `json:"key"`
+				} `json:"issues"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			keys := []string{}
+			for _, issue := range body.Issues {
+				keys = append(keys, issue.Key)
+			}
+			assert.Equal(t, tt.expectedKeys, keys)
+		})
+	}
+}
+
+func TestFakeJiraFields(t *testing.T) {
+	_, server := newFakeJiraServer(t, "", "")
+
+	resp, err := http.Get(server.URL + "/rest/api/2/field")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var fields []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&fields))
+	assert.Contains(t, fields, struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}{ID: "customfield_10020", Name: "Sprint"})
+}
+
+func TestFakeJiraFaults(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "issues"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "issues", "EV-7.json"), []byte(`{"fields": {"status": {"name": "Done"}}}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "faults.json"), []byte(`{"EV-7": {"status": 429, "retry_after": 5, "times": 2}}`), 0644))
+
+	fake, err := NewFakeJira(os.DirFS(dir))
+	require.NoError(t, err)
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	// The first two requests are rate limited, then the fixture is served
+	for i := 0; i < 2; i++ {
+		resp, err := http.Get(server.URL + "/rest/api/2/issue/EV-7")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, "5", resp.Header.Get("Retry-After"))
+	}
+	status, issue := getFakeJSON(t, server, "/rest/api/2/issue/EV-7")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "EV-7", issue["key"], "the key defaults to the file name")
+	assert.Len(t, fake.Requests(), 3)
+
+	_, err = NewFakeJira(os.DirFS(t.TempDir()))
+	assert.Error(t, err, "fixtures without an issues directory are rejected")
+}
+
+func TestFakeJiraWithClient(t *testing.T) {
+	_, server := newFakeJiraServer(t, "ci@example.com", "fake-token")
+
+	config := &AppConfig{
+		JIRAURL:      server.URL,
+		JIRAUsername: "ci@example.com",
+		JIRAToken:    "fake-token",
+	}
+	client, err := NewJiraClientFromConfig(config)
+	require.NoError(t, err)
+
+	response := client.FetchJiraDetails([]string{"EV-1", "EV-404", "EV-401", "EV-429"})
+	require.Len(t, response.Tasks, 4)
+
+	done := response.Tasks[0]
+	assert.Equal(t, "Done", done.Status)
+	require.NotNil(t, done.Assignee)
+	assert.Equal(t, "Jane Smith", *done.Assignee)
+	assert.Equal(t, "Add evidence signing to the release pipeline", done.Summary)
+	assert.Equal(t, []string{"security"}, done.Labels)
+	assert.Equal(t, []string{"Pipeline"}, done.Components)
+	require.Len(t, done.Transitions, 3)
+	assert.Equal(t, "In Review", done.Transitions[2].FromStatus)
+	require.Len(t, done.FieldChanges, 1)
+	assert.Equal(t, FieldChange{Field: "assignee", ToValue: "Jane Smith", Author: done.Transitions[0].Author,
+		AuthorEmail: done.Transitions[0].AuthorEmail, ChangeTime: done.Transitions[0].TransitionTime}, done.FieldChanges[0])
+
+	assert.Equal(t, ErrorCodeNotFound, response.Tasks[1].Error.Code)
+	assert.Equal(t, ErrorCodeUnauthorized, response.Tasks[2].Error.Code)
+	assert.Equal(t, ErrorCodeRateLimited, response.Tasks[3].Error.Code)
+
+	// Wrong credentials are rejected for every issue
+	config.JIRAToken = "wrong-token"
+	client, err = NewJiraClientFromConfig(config)
+	require.NoError(t, err)
+	response = client.FetchJiraDetails([]string{"EV-1"})
+	require.NotNil(t, response.Tasks[0].Error)
+	assert.Equal(t, ErrorCodeUnauthorized, response.Tasks[0].Error.Code)
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Stable policy rule IDs, used by the gate and by machine-readable reports
+const (
+	RuleFetchError         = "jira/fetch-error"
+	RuleStatusNotAllowed   = "jira/status-not-allowed"
+	RuleNoTasks            = "jira/no-tasks"
+	RuleSeparationOfDuties = "jira/separation-of-duties"
+	RuleUntrackedCommit    = "git/untracked-commit"
+)
+
+// policyRuleDescriptions documents every rule the gate can report
+var policyRuleDescriptions = map[string]string{
+	RuleFetchError:         "The ticket could not be fetched from JIRA",
+	RuleStatusNotAllowed:   "The ticket is not in an allowed status",
+	RuleNoTasks:            "The evidence references no JIRA tickets",
+	RuleSeparationOfDuties: "The ticket was moved into its current status by its own assignee",
+	RuleUntrackedCommit:    "The commit message references no JIRA ticket",
+}
+
+// GatePolicy configures which tickets pass the gate
+type GatePolicy struct {
+	// AllowedStatuses lists the statuses a ticket must be in (case-insensitive); empty allows any status
+	AllowedStatuses []string
+	// AllowErrors lets tickets that could not be fetched pass
+	AllowErrors bool
+	// RequireTasks fails the gate when the evidence has no tickets at all
+	RequireTasks bool
+	// SeparationOfDuties fails tickets that their assignee moved into their current status
+	SeparationOfDuties bool
+}
+
+// PolicyViolation is a single rule a ticket (or the evidence as a whole) failed
+type PolicyViolation struct {
+	RuleID  string
+	Message string
+	// Commit is the SHA of the commit the violation is about, empty for ticket violations
+	Commit string
+}
+
+// PolicyResult is the gate outcome for one ticket
+type PolicyResult struct {
+	Task       JiraTransitionResult
+	Violations []PolicyViolation
+}
+
+// Passed reports whether the ticket satisfied every rule
+func (r PolicyResult) Passed() bool {
+	return len(r.Violations) == 0
+}
+
+// GateReport is the gate outcome for a whole evidence file
+type GateReport struct {
+	Results []PolicyResult
+	// Violations holds failures that are not tied to a single ticket
+	Violations []PolicyViolation
+	// Commits are the commits checked by checkCommits, nil when only the evidence was checked
+	Commits []GitCommit
+}
+
+// Passed reports whether the evidence satisfied every rule
+func (g *GateReport) Passed() bool {
+	return g.FailedCount() == 0 && len(g.Violations) == 0
+}
+
+// FailedCount returns the number of tickets with at least one violation
+func (g *GateReport) FailedCount() int {
+	failed := 0
+	for _, result := range g.Results {
+		if !result.Passed() {
+			failed++
+		}
+	}
+	return failed
+}
+
+// evaluatePolicy checks every ticket in the response against the policy
+func evaluatePolicy(response TransitionCheckResponse, policy GatePolicy) *GateReport {
+	report := &GateReport{Results: make([]PolicyResult, 0, len(response.Tasks))}
+
+	if policy.RequireTasks && len(response.Tasks) == 0 {
+		report.Violations = append(report.Violations, PolicyViolation{
+			RuleID:  RuleNoTasks,
+			Message: "no JIRA tickets found in evidence",
+		})
+	}
+
+	for _, task := range response.Tasks {
+		result := PolicyResult{Task: task}
+
+		if task.Status == ErrorStatus {
+			if !policy.AllowErrors {
+				result.Violations = append(result.Violations, PolicyViolation{
+					RuleID:  RuleFetchError,
+					Message: fmt.Sprintf("%s could not be fetched: %s", task.Key, taskErrorMessage(task)),
+				})
+			}
+		} else if len(policy.AllowedStatuses) > 0 && !statusIn(task.Status, policy.AllowedStatuses) {
+			result.Violations = append(result.Violations, PolicyViolation{
+				RuleID: RuleStatusNotAllowed,
+				Message: fmt.Sprintf("%s is '%s', expected one of: %s",
+					task.Key, task.Status, strings.Join(policy.AllowedStatuses, ", ")),
+			})
+		}
+
+		if policy.SeparationOfDuties && task.Status != ErrorStatus {
+			if author := statusAuthor(task); author != "" && author == stringValue(task.Assignee) {
+				result.Violations = append(result.Violations, PolicyViolation{
+					RuleID:  RuleSeparationOfDuties,
+					Message: fmt.Sprintf("%s was moved to '%s' by its assignee %s", task.Key, task.Status, author),
+				})
+			}
+		}
+
+		report.Results = append(report.Results, result)
+	}
+
+	return report
+}
+
+// statusAuthor returns who last moved a ticket into its current status, empty when no transition did
+func statusAuthor(task JiraTransitionResult) string {
+	author := ""
+	for _, transition := range sortedTransitions(task.Transitions) {
+		if strings.EqualFold(transition.ToStatus, task.Status) {
+			author = transition.Author
+		}
+	}
+	return author
+}
+
+// checkCommits records the commits a release consists of and fails every commit that references no ticket
+func checkCommits(report *GateReport, commits []GitCommit) {
+	report.Commits = commits
+	for _, commit := range commits {
+		if len(commit.JiraIDs) == 0 {
+			report.Violations = append(report.Violations, PolicyViolation{
+				RuleID:  RuleUntrackedCommit,
+				Message: fmt.Sprintf("commit %s references no JIRA ticket: %s", shortSHA(commit.SHA), commit.Subject),
+				Commit:  commit.SHA,
+			})
+		}
+	}
+}
+
+// commitsReferencing returns the SHAs of the checked commits that reference a ticket (case-insensitive)
+func (g *GateReport) commitsReferencing(key string) []string {
+	var shas []string
+	for _, commit := range g.Commits {
+		for _, jiraID := range commit.JiraIDs {
+			if strings.EqualFold(jiraID, key) {
+				shas = append(shas, commit.SHA)
+				break
+			}
+		}
+	}
+	return shas
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
+
+// taskErrorMessage returns the most specific error message available for an error task
+func taskErrorMessage(task JiraTransitionResult) string {
+	if task.Error != nil {
+		if task.Error.Code != ErrorCodeUnknown {
+			return fmt.Sprintf("%s (%s)", task.Error.Message, task.Error.Code)
+		}
+		return task.Error.Message
+	}
+	return strings.TrimPrefix(task.Description, "Error: ")
+}
+
+// printGateReport prints one line per ticket followed by a summary
+func printGateReport(report *GateReport) {
+	for _, result := range report.Results {
+		if result.Passed() {
+			fmt.Printf("✅ %s (%s)\n", result.Task.Key, result.Task.Status)
+			continue
+		}
+		for _, violation := range result.Violations {
+			fmt.Printf("❌ [%s] %s\n", violation.RuleID, violation.Message)
+		}
+	}
+	for _, violation := range report.Violations {
+		fmt.Printf("❌ [%s] %s\n", violation.RuleID, violation.Message)
+	}
+
+	fmt.Println("")
+	fmt.Printf("Tickets: %d, passed: %d, failed: %d\n",
+		len(report.Results), len(report.Results)-report.FailedCount(), report.FailedCount())
+	if report.Commits != nil {
+		untracked := 0
+		for _, violation := range report.Violations {
+			if violation.RuleID == RuleUntrackedCommit {
+				untracked++
+			}
+		}
+		fmt.Printf("Commits: %d, untracked: %d\n", len(report.Commits), untracked)
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluatePolicy(t *testing.T) {
+	response := TransitionCheckResponse{
+		Tasks: []JiraTransitionResult{
+			{Key: "EV-1", Status: "Done"},
+			{Key: "EV-2", Status: "In Progress"},
+			{
+				Key:    "EV-3",
+				Status: ErrorStatus,
+				Error:  &TaskError{Code: ErrorCodeNotFound, HTTPStatus: 404, Message: "issue does not exist"},
+			},
+			{Key: "EV-4", Status: "closed"},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		response       TransitionCheckResponse
+		policy         GatePolicy
+		expectedPassed bool
+		expectedFailed int
+		expectedRules  map[string]string
+	}{
+		{
+			name:           "Statuses and errors enforced",
+			response:       response,
+			policy:         GatePolicy{AllowedStatuses: []string{"Done", "Closed"}},
+			expectedPassed: false,
+			expectedFailed: 2,
+			expectedRules: map[string]string{
+				"EV-2": RuleStatusNotAllowed,
+				"EV-3": RuleFetchError,
+			},
+		},
+		{
+			name:           "Errors allowed",
+			response:       response,
+			policy:         GatePolicy{AllowedStatuses: []string{"Done", "Closed"}, AllowErrors: true},
+			expectedPassed: false,
+			expectedFailed: 1,
+			expectedRules:  map[string]string{"EV-2": RuleStatusNotAllowed},
+		},
+		{
+			name:           "Any status allowed",
+			response:       response,
+			policy:         GatePolicy{AllowErrors: true},
+			expectedPassed: true,
+			expectedRules:  map[string]string{},
+		},
+		{
+			name:           "Empty evidence passes by default",
+			response:       TransitionCheckResponse{},
+			policy:         GatePolicy{AllowedStatuses: []string{"Done"}},
+			expectedPassed: true,
+			expectedRules:  map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := evaluatePolicy(tt.response, tt.policy)
+
+			assert.Equal(t, tt.expectedPassed, report.Passed())
+			assert.Equal(t, tt.expectedFailed, report.FailedCount())
+			require.Len(t, report.Results, len(tt.response.Tasks))
+
+			rules := map[string]string{}
+			for _, result := range report.Results {
+				for _, violation := range result.Violations {
+					rules[result.Task.Key] = violation.RuleID
+				}
+			}
+			assert.Equal(t, tt.expectedRules, rules)
+		})
+	}
+}
+
+func TestEvaluatePolicyRequireTasks(t *testing.T) {
+	report := evaluatePolicy(TransitionCheckResponse{}, GatePolicy{RequireTasks: true})
+
+	assert.False(t, report.Passed())
+	assert.Equal(t, 0, report.FailedCount())
+	require.Len(t, report.Violations, 1)
+	assert.Equal(t, RuleNoTasks, report.Violations[0].RuleID)
+}
+
+func TestEvaluatePolicySeparationOfDuties(t *testing.T) {
+	response := TransitionCheckResponse{
+		Tasks: []JiraTransitionResult{
+			{Key: "EV-1", Status: "Done", Assignee: strPtr("Alice"), Transitions: []Transition{
+				{FromStatus: "In Review", ToStatus: "Done", Author: "Bob", TransitionTime: "2025-01-02T10:00:00.000+0000"},
+				{FromStatus: "To Do", ToStatus: "In Review", Author: "Alice", TransitionTime: "2025-01-01T10:00:00.000+0000"},
+			}},
+			// Reopened and closed again by the assignee: the latest move into Done counts
+			{Key: "EV-2", Status: "Done", Assignee: strPtr("Alice"), Transitions: []Transition{
+				{FromStatus: "In Review", ToStatus: "Done", Author: "Bob", TransitionTime: "2025-01-02T10:00:00.000+0000"},
+				{FromStatus: "Done", ToStatus: "In Review", Author: "Alice", TransitionTime: "2025-01-03T10:00:00.000+0000"},
+				{FromStatus: "In Review", ToStatus: "done", Author: "Alice", TransitionTime: "2025-01-04T10:00:00.000+0000"},
+			}},
+			{Key: "EV-3", Status: "Done", Transitions: []Transition{}},
+		},
+	}
+
+	report := evaluatePolicy(response, GatePolicy{SeparationOfDuties: true})
+	assert.Equal(t, 1, report.FailedCount())
+	assert.True(t, report.Results[0].Passed())
+	require.Len(t, report.Results[1].Violations, 1)
+	assert.Equal(t, RuleSeparationOfDuties, report.Results[1].Violations[0].RuleID)
+	assert.Equal(t, "EV-2 was moved to 'Done' by its assignee Alice", report.Results[1].Violations[0].Message)
+	assert.True(t, report.Results[2].Passed())
+
+	// The rule is opt-in
+	assert.True(t, evaluatePolicy(response, GatePolicy{}).Passed())
+}
+
+func TestCheckCommits(t *testing.T) {
+	report := evaluatePolicy(TransitionCheckResponse{Tasks: []JiraTransitionResult{{Key: "EV-1", Status: "Done"}}}, GatePolicy{})
+	checkCommits(report, []GitCommit{
+		{SHA: "1111111111111111111111111111111111111111", Subject: "EV-1 Fix login", JiraIDs: []string{"EV-1"}},
+		{SHA: "2222222222222222222222222222222222222222", Subject: "Bump dependencies"},
+		{SHA: "3333333333333333333333333333333333333333", Subject: "ev-1 follow-up", JiraIDs: []string{"ev-1"}},
+	})
+
+	assert.False(t, report.Passed())
+	require.Len(t, report.Violations, 1)
+	assert.Equal(t, PolicyViolation{
+		RuleID:  RuleUntrackedCommit,
+		Message: "commit 222222222222 references no JIRA ticket: Bump dependencies",
+		Commit:  "2222222222222222222222222222222222222222",
+	}, report.Violations[0])
+
+	assert.Equal(t, []string{"1111111111111111111111111111111111111111", "3333333333333333333333333333333333333333"},
+		report.commitsReferencing("EV-1"))
+	assert.Empty(t, report.commitsReferencing("EV-2"))
+}
+
+func TestTaskErrorMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		task     JiraTransitionResult
+		expected string
+	}{
+		{
+			name:     "Classified error",
+			task:     JiraTransitionResult{Error: &TaskError{Code: ErrorCodeUnauthorized, Message: "bad credentials"}},
+			expected: "bad credentials (unauthorized)",
+		},
+		{
+			name:     "Unclassified error",
+			task:     JiraTransitionResult{Error: &TaskError{Code: ErrorCodeUnknown, Message: "boom"}},
+			expected: "boom",
+		},
+		{
+			name:     "Description fallback",
+			task:     JiraTransitionResult{Description: "Error: Could not retrieve issue"},
+			expected: "Could not retrieve issue",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, taskErrorMessage(tt.task))
+		})
+	}
+}
+
+func TestPolicyRuleDescriptions(t *testing.T) {
+	for _, rule := range []string{RuleFetchError, RuleStatusNotAllowed, RuleNoTasks, RuleSeparationOfDuties, RuleUntrackedCommit} {
+		assert.NotEmpty(t, policyRuleDescriptions[rule], "rule %s has no description", rule)
+	}
+}
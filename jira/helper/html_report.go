@@ -0,0 +1,261 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"os"
+	"strings"
+	"time"
+)
+
+// htmlReportTemplate is the self-contained HTML layout; styles and scripts are inlined so the page works offline
+//
+//go:embed templates/report.html.tmpl
+var htmlReportTemplate string
+
+// Timeline chart geometry, in SVG user units
+const (
+	timelineLabelWidth = 120
+	timelinePlotWidth  = 760
+	timelineRowHeight  = 22
+	timelineAxisHeight = 24
+	timelineTickCount  = 5
+)
+
+// htmlReportData is the data model of the HTML report: the report data plus the precomputed timeline
+type htmlReportData struct {
+	ReportData
+	Timeline *timelineChart
+}
+
+// timelineChart lays out the status history of every ticket on a shared time axis
+type timelineChart struct {
+	Width  int
+	Height int
+	Rows   []timelineRow
+	Ticks  []timelineTick
+}
+
+// timelineRow is the status history of one ticket
+type timelineRow struct {
+	Key      string
+	Y        int
+	Segments []timelineSegment
+}
+
+// timelineSegment is one period a ticket spent in a status
+type timelineSegment struct {
+	X        float64
+	Width    float64
+	Status   string
+	Category string
+	Title    string
+}
+
+// timelineTick is a labelled date on the time axis
+type timelineTick struct {
+	X     float64
+	Label string
+}
+
+// timelineSpan is a period in a status, before it is scaled to the chart
+type timelineSpan struct {
+	start  time.Time
+	end    time.Time
+	status string
+}
+
+// GenerateHTMLFromJSON reads a JSON file and renders it as a single self-contained HTML page
+func GenerateHTMLFromJSON(inputFile, outputFile string, opts ReportOptions) error {
+	response, report, err := loadTransitionResponse(inputFile)
+	if err != nil {
+		return err
+	}
+	if report.FromVersion != report.ToVersion {
+		fmt.Printf("Upgraded evidence from schema %s to %s (%d values defaulted)\n",
+			report.FromVersion, report.ToVersion, len(report.Changes))
+	}
+
+	response.Tasks = sortedTasks(response.Tasks, opts.TaskOrder)
+	html, err := generateHTML(response, inputFile, opts.generatedAt(), opts)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(outputFile, []byte(html), 0644); err != nil {
+		return fmt.Errorf("error writing HTML file: %v", err)
+	}
+
+	fmt.Printf("HTML file generated: %s\n", outputFile)
+	return nil
+}
+
+// generateHTML renders JIRA data with the HTML report layout
+func generateHTML(response TransitionCheckResponse, inputFile string, generatedAt time.Time, opts ReportOptions) (string, error) {
+	tmpl, err := template.New("report.html.tmpl").Funcs(htmlReportFuncs(opts)).Parse(htmlReportTemplate)
+	if err != nil {
+		return "", &ValidationError{Field: "template", Value: "report.html.tmpl", Err: err}
+	}
+
+	data := htmlReportData{
+		ReportData: newReportData(response, inputFile, generatedAt),
+		Timeline:   buildTimeline(response, opts),
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("error rendering template %s: %v", tmpl.Name(), err)
+	}
+	return sb.String(), nil
+}
+
+// htmlReportFuncs returns the helper functions of the HTML layout
+func htmlReportFuncs(opts ReportOptions) template.FuncMap {
+	return template.FuncMap{
+		"formatDate":          formatDate,
+		"formatHours":         formatHours,
+		"formatOptionalHours": formatOptionalHours,
+		"statusCategory": func(status string) string {
+			return statusCategory(status, opts)
+		},
+		"assigneeName": assigneeName,
+		"errorSummary": errorSummary,
+		"join":         strings.Join,
+		"joinOrNone":   joinOrNone,
+		"sortTime":     sortTime,
+		"sortHours":    sortHours,
+		"add": func(a, b int) int {
+			return a + b
+		},
+	}
+}
+
+// sortTime returns the Unix time of a JIRA timestamp as a sort key, 0 when it cannot be parsed
+func sortTime(value string) int64 {
+	t, err := parseJiraTime(value)
+	if err != nil {
+		return 0
+	}
+	return t.Unix()
+}
+
+// sortHours returns an optional duration as a sort key, -1 when unset so missing values sort first
+func sortHours(hours *float64) float64 {
+	if hours == nil {
+		return -1
+	}
+	return *hours
+}
+
+// buildTimeline lays out the status history of every ticket that has a creation date.
+// The axis ends at the --as-of instant, or at the latest timestamp in the evidence for current state,
+// so rendering the same evidence twice draws the same chart. Returns nil when there is nothing to draw.
+func buildTimeline(response TransitionCheckResponse, opts ReportOptions) *timelineChart {
+	var end time.Time
+	if asOf, err := parseJiraTime(response.AsOf); err == nil {
+		end = asOf
+	} else {
+		for _, task := range response.Tasks {
+			if updated, err := parseJiraTime(task.Updated); err == nil && updated.After(end) {
+				end = updated
+			}
+			for _, transition := range task.Transitions {
+				if at, err := parseJiraTime(transition.TransitionTime); err == nil && at.After(end) {
+					end = at
+				}
+			}
+		}
+	}
+
+	type taskSpans struct {
+		key   string
+		spans []timelineSpan
+	}
+	var tasks []taskSpans
+	var start time.Time
+	for _, task := range response.Tasks {
+		spans := statusSpans(task, end)
+		if len(spans) == 0 {
+			continue
+		}
+		if start.IsZero() || spans[0].start.Before(start) {
+			start = spans[0].start
+		}
+		tasks = append(tasks, taskSpans{key: task.Key, spans: spans})
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	total := end.Sub(start)
+	if total <= 0 {
+		total = time.Hour
+	}
+	scale := func(t time.Time) float64 {
+		return timelineLabelWidth + float64(t.Sub(start))/float64(total)*timelinePlotWidth
+	}
+
+	chart := &timelineChart{
+		Width:  timelineLabelWidth + timelinePlotWidth,
+		Height: timelineAxisHeight + len(tasks)*timelineRowHeight,
+	}
+	// Ticks of short timelines would repeat the same day, so they show the time as well
+	tickFormat := "2006-01-02"
+	if total < timelineTickCount*24*time.Hour {
+		tickFormat = "2006-01-02 15:04"
+	}
+	for i := 0; i < timelineTickCount; i++ {
+		at := start.Add(total * time.Duration(i) / (timelineTickCount - 1))
+		chart.Ticks = append(chart.Ticks, timelineTick{X: scale(at), Label: at.Format(tickFormat)})
+	}
+	for i, task := range tasks {
+		row := timelineRow{Key: task.key, Y: timelineAxisHeight + i*timelineRowHeight}
+		for _, span := range task.spans {
+			x := scale(span.start)
+			row.Segments = append(row.Segments, timelineSegment{
+				X:        x,
+				Width:    scale(span.end) - x,
+				Status:   span.status,
+				Category: statusCategory(span.status, opts),
+				Title: fmt.Sprintf("%s: %s, %s to %s", task.key, span.status,
+					span.start.Format("2006-01-02 15:04"), span.end.Format("2006-01-02 15:04")),
+			})
+		}
+		chart.Rows = append(chart.Rows, row)
+	}
+	return chart
+}
+
+// statusSpans returns the periods a ticket spent in each status from its creation until end.
+// Tickets without a parseable creation date, such as those that could not be fetched, have no spans.
+func statusSpans(task JiraTransitionResult, end time.Time) []timelineSpan {
+	created, err := parseJiraTime(task.Created)
+	if err != nil {
+		return nil
+	}
+
+	transitions := sortedTransitions(task.Transitions)
+	status := task.Status
+	if len(transitions) > 0 {
+		status = transitions[0].FromStatus
+	}
+
+	var spans []timelineSpan
+	from := created
+	for _, transition := range transitions {
+		at, err := parseJiraTime(transition.TransitionTime)
+		if err != nil {
+			continue
+		}
+		if at.After(from) {
+			spans = append(spans, timelineSpan{start: from, end: at, status: status})
+			from = at
+		}
+		status = transition.ToStatus
+	}
+	if end.Before(from) {
+		end = from
+	}
+	return append(spans, timelineSpan{start: from, end: end, status: status})
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateHTML(t *testing.T) {
+	lead := 47.0
+	tests := []struct {
+		name     string
+		response TransitionCheckResponse
+		checks   []string
+		absent   []string
+	}{
+		{
+			name: "Finished ticket",
+			response: TransitionCheckResponse{
+				SchemaVersion: CurrentSchemaVersion,
+				Tasks: []JiraTransitionResult{
+					{
+						Key:      "EV-1",
+						Link:     "https://example.atlassian.net/browse/EV-1",
+						Status:   "Done",
+						Type:     "Task",
+						Created:  "2025-01-01T10:00:00.000+0000",
+						Updated:  "2025-01-03T09:00:00.000+0000",
+						Assignee: strPtr("John Doe"),
+						Transitions: []Transition{
+							{FromStatus: "In Progress", ToStatus: "Done", TransitionTime: "2025-01-03T09:00:00.000+0000"},
+							{FromStatus: "To Do", ToStatus: "In Progress", TransitionTime: "2025-01-02T10:00:00.000+0000"},
+						},
+						Metrics: &TaskMetrics{LeadTimeHours: &lead},
+					},
+				},
+			},
+			checks: []string{
+				"Evidence schema: " + CurrentSchemaVersion + " (evidence.json)",
+				"Total tasks: 1",
+				`<span class="chip done">Done: 1</span>`,
+				`<table class="sortable">`,
+				`<td data-sort="1735894800">2025-01-03 09:00:00</td>`,
+				`<td data-sort="47">1d 23h</td>`,
+				`<details class="task" id="task-EV-1">`,
+				"<summary>Transition history (2)</summary>",
+				`<svg class="timeline"`,
+				"<title>EV-1: To Do, 2025-01-01 10:00 to 2025-01-02 10:00</title>",
+			},
+		},
+		{
+			name: "Ticket that could not be fetched",
+			response: TransitionCheckResponse{Tasks: []JiraTransitionResult{
+				{Key: "EV-3", Status: ErrorStatus, Type: ErrorType, Transitions: []Transition{},
+					Error: &TaskError{Code: ErrorCodeNotFound, HTTPStatus: 404, Message: "Issue does not exist"}},
+			}},
+			checks: []string{
+				`<span class="chip error">Error: 1</span>`,
+				"not_found (HTTP 404): Issue does not exist",
+			},
+		},
+		{
+			name: "Ticket text is escaped",
+			response: TransitionCheckResponse{Tasks: []JiraTransitionResult{
+				{Key: "EV-2", Status: "In Progress", Description: "Fix <script>alert(1)</script> rendering", Transitions: []Transition{}},
+			}},
+			checks: []string{"Fix &lt;script&gt;alert(1)&lt;/script&gt; rendering"},
+			absent: []string{"<script>alert(1)"},
+		},
+		{
+			name:     "Sections without data are omitted",
+			response: TransitionCheckResponse{Tasks: []JiraTransitionResult{}},
+			checks:   []string{"Total tasks: 0"},
+			absent:   []string{"<h2>Timeline</h2>", "<h2>Flow Metrics</h2>", "<h2>Changes Since Previous Evidence</h2>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html, err := generateHTML(tt.response, "evidence.json", time.Date(2025, 1, 4, 12, 0, 0, 0, time.UTC), ReportOptions{})
+			require.NoError(t, err)
+
+			for _, check := range tt.checks {
+				assert.Contains(t, html, check)
+			}
+			for _, absent := range tt.absent {
+				assert.NotContains(t, html, absent)
+			}
+
+			// Every page is self-contained and loads nothing over the network
+			assert.True(t, strings.HasPrefix(html, "<!DOCTYPE html>"))
+			assert.Contains(t, html, "Generated on: 2025-01-04 12:00:00")
+			assert.NotContains(t, html, "<link")
+			assert.NotContains(t, html, `src="http`)
+		})
+	}
+}
+
+func TestBuildTimeline(t *testing.T) {
+	response := TransitionCheckResponse{Tasks: []JiraTransitionResult{
+		{
+			Key:     "EV-1",
+			Status:  "Done",
+			Created: "2025-01-01T10:00:00.000+0000",
+			Transitions: []Transition{
+				{FromStatus: "In Progress", ToStatus: "Done", TransitionTime: "2025-01-03T09:00:00.000+0000"},
+				{FromStatus: "To Do", ToStatus: "In Progress", TransitionTime: "2025-01-02T10:00:00.000+0000"},
+			},
+		},
+		{Key: "EV-2", Status: "In Progress", Created: "2025-01-02T10:00:00.000+0000", Transitions: []Transition{}},
+		{Key: "EV-3", Status: ErrorStatus, Transitions: []Transition{}},
+	}}
+	chart := buildTimeline(response, ReportOptions{})
+	require.NotNil(t, chart)
+
+	// The ticket that could not be fetched has no creation date and is left out
+	require.Len(t, chart.Rows, 2)
+	assert.Equal(t, timelineLabelWidth+timelinePlotWidth, chart.Width)
+	assert.Equal(t, timelineAxisHeight+2*timelineRowHeight, chart.Height)
+	require.Len(t, chart.Ticks, timelineTickCount)
+	assert.Equal(t, "2025-01-01 10:00", chart.Ticks[0].Label)
+	assert.Equal(t, "2025-01-03 09:00", chart.Ticks[timelineTickCount-1].Label)
+
+	// EV-1 spans the whole axis: a day in To Do, 23 hours in progress, then done
+	ev1 := chart.Rows[0]
+	assert.Equal(t, "EV-1", ev1.Key)
+	require.Len(t, ev1.Segments, 3)
+	assert.Equal(t, []string{"To Do", "In Progress", "Done"},
+		[]string{ev1.Segments[0].Status, ev1.Segments[1].Status, ev1.Segments[2].Status})
+	assert.Equal(t, []string{StatusCategoryOther, StatusCategoryInProgress, StatusCategoryDone},
+		[]string{ev1.Segments[0].Category, ev1.Segments[1].Category, ev1.Segments[2].Category})
+	assert.InDelta(t, float64(timelineLabelWidth), ev1.Segments[0].X, 0.001)
+	assert.InDelta(t, float64(timelinePlotWidth)*24/47, ev1.Segments[0].Width, 0.001)
+	assert.InDelta(t, 0, ev1.Segments[2].Width, 0.001)
+
+	// EV-2 never moved, so it stays in its current status until the end of the axis
+	ev2 := chart.Rows[1]
+	require.Len(t, ev2.Segments, 1)
+	assert.Equal(t, "In Progress", ev2.Segments[0].Status)
+	assert.InDelta(t, float64(timelineLabelWidth+timelinePlotWidth), ev2.Segments[0].X+ev2.Segments[0].Width, 0.001)
+
+	t.Run("As-of evidence ends at the as-of instant", func(t *testing.T) {
+		asOf := response
+		asOf.AsOf = "2025-01-10T10:00:00.000+0000"
+		chart := buildTimeline(asOf, ReportOptions{})
+		require.NotNil(t, chart)
+		assert.Equal(t, "2025-01-01", chart.Ticks[0].Label)
+		assert.Equal(t, "2025-01-10", chart.Ticks[timelineTickCount-1].Label)
+	})
+
+	t.Run("No ticket with a creation date", func(t *testing.T) {
+		response := TransitionCheckResponse{Tasks: []JiraTransitionResult{{Key: "EV-3", Status: ErrorStatus}}}
+		assert.Nil(t, buildTimeline(response, ReportOptions{}))
+	})
+}
+
+func TestGenerateHTMLFromJSON(t *testing.T) {
+	dir := t.TempDir()
+	inputFile := filepath.Join(dir, "evidence.json")
+	require.NoError(t, os.WriteFile(inputFile, []byte(`{"tasks": [{"key": "EV-2", "status": "In Progress", "transitions": []}]}`), 0644))
+
+	outputFile := filepath.Join(dir, "report.html")
+	require.NoError(t, GenerateHTMLFromJSON(inputFile, outputFile, ReportOptions{}))
+
+	html, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(html), "<!DOCTYPE html>"))
+	assert.Contains(t, string(html), `id="task-EV-2"`)
+
+	err = GenerateHTMLFromJSON(filepath.Join(dir, "missing.json"), outputFile, ReportOptions{})
+	assert.Error(t, err)
+}
+
+func TestRunMarkdownModeHTML(t *testing.T) {
+	dir := t.TempDir()
+	inputFile := filepath.Join(dir, "evidence.json")
+	require.NoError(t, os.WriteFile(inputFile, []byte(`{"tasks": [{"key": "EV-2", "status": "In Progress", "transitions": []}]}`), 0644))
+	outputFile := filepath.Join(dir, "report.html")
+
+	err := runMarkdownMode(&FlagConfig{InputFile: inputFile, MarkdownOutput: outputFile, Format: "HTML"}, &AppConfig{})
+	require.NoError(t, err)
+	_, err = os.Stat(outputFile)
+	assert.NoError(t, err)
+
+	err = runMarkdownMode(&FlagConfig{InputFile: inputFile, Format: "html", Template: "custom.tmpl"}, &AppConfig{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "templates apply to markdown reports only")
+
+	err = runMarkdownMode(&FlagConfig{InputFile: inputFile, Format: "pdf"}, &AppConfig{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected markdown or html")
+}
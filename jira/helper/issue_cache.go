@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// DefaultCacheTTL is how long cached issues are used without asking JIRA whether they changed
+const DefaultCacheTTL = 15 * time.Minute
+
+// IssueCache stores fetched issues on disk, keyed by JIRA instance and issue key.
+// Entries younger than TTL are used as is; older entries are revalidated by comparing the issue's
+// "updated" timestamp, so unchanged issues skip the full changelog download.
+type IssueCache struct {
+	Dir string
+	TTL time.Duration
+	// Offline serves strictly from the cache and never contacts JIRA
+	Offline bool
+
+	now func() time.Time
+}
+
+// cachedIssue is one cache file: the issue exactly as JIRA returned it with expand=changelog
+type cachedIssue struct {
+	JIRAURL   string          `json:"jira_url"`
+	Key       string          `json:"key"`
+	Updated   string          `json:"updated"`
+	FetchedAt time.Time       `json:"fetched_at"`
+	Issue     json.RawMessage `json:"issue"`
+}
+
+// NewIssueCache creates a cache in dir
+func NewIssueCache(dir string, ttl time.Duration, offline bool) *IssueCache {
+	return &IssueCache{Dir: dir, TTL: ttl, Offline: offline, now: time.Now}
+}
+
+// path returns the cache file of an issue; each JIRA instance gets its own directory.
+// Keys containing path separators are refused, so no key can reach outside the cache directory.
+func (c *IssueCache) path(jiraURL, key string) (string, error) {
+	name := strings.ToUpper(key) + ".json"
+	if key == "" || strings.ContainsAny(key, `/\`) || !filepath.IsLocal(name) {
+		return "", &ValidationError{Field: "JIRA ID", Value: key, Err: fmt.Errorf("cannot be used as a cache file name")}
+	}
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimRight(jiraURL, "/"))))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:8]), name), nil
+}
+
+// load returns the cached issue, or nil when there is none
+func (c *IssueCache) load(jiraURL, key string) (*cachedIssue, error) {
+	path, err := c.path(jiraURL, key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entry cachedIssue
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("invalid cache entry for %s: %v", key, err)
+	}
+	if !strings.EqualFold(strings.TrimRight(entry.JIRAURL, "/"), strings.TrimRight(jiraURL, "/")) {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+// store writes an issue to the cache; the files hold ticket data, so they are private to the user
+func (c *IssueCache) store(entry *cachedIssue) error {
+	path, err := c.path(entry.JIRAURL, entry.Key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %v", err)
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// fresh reports whether an entry may be used without revalidation
+func (c *IssueCache) fresh(entry *cachedIssue) bool {
+	return c.now().Sub(entry.FetchedAt) < c.TTL
+}
+
+// getIssueCached returns the issue from the cache when possible, revalidating stale entries against JIRA
+func (jc *JiraClient) getIssueCached(ctx context.Context, jiraID string) (*jira.Issue, *jira.Response, error) {
+	cache := jc.cache
+	// A key the cache refuses is not sent to JIRA either
+	if _, err := cache.path(jc.baseURL, jiraID); err != nil {
+		return nil, nil, err
+	}
+	entry, err := cache.load(jc.baseURL, jiraID)
+	if err != nil {
+		// A damaged entry is refetched rather than failing the run
+		fmt.Fprintf(os.Stderr, "Ignoring cached JIRA %s: %v\n", jiraID, err)
+		entry = nil
+	}
+
+	if cache.Offline {
+		if entry == nil {
+			return nil, nil, fmt.Errorf("JIRA %s is not cached and --offline forbids fetching it", jiraID)
+		}
+		return decodeCachedIssue(entry)
+	}
+
+	if entry != nil {
+		if cache.fresh(entry) {
+			return decodeCachedIssue(entry)
+		}
+
+		// Only the updated timestamp is requested; the changelog is downloaded again only when it changed
+		updated, err := jc.fetchIssueUpdated(ctx, jiraID)
+		if err == nil && updated == entry.Updated {
+			entry.FetchedAt = cache.now()
+			if err := cache.store(entry); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to update cached JIRA %s: %v\n", jiraID, err)
+			}
+			return decodeCachedIssue(entry)
+		}
+		// On a failed revalidation the full fetch below reports the error
+	}
+
+	var raw json.RawMessage
+	resp, err := jc.getRaw(ctx, jiraID, "expand=changelog", &raw)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	entry = &cachedIssue{JIRAURL: jc.baseURL, Key: strings.ToUpper(jiraID), FetchedAt: cache.now(), Issue: raw}
+	issue, _, err := decodeCachedIssue(entry)
+	if err != nil {
+		return nil, resp, err
+	}
+	if issue.Fields != nil {
+		entry.Updated = time.Time(issue.Fields.Updated).UTC().Format(time.RFC3339Nano)
+	}
+	if err := cache.store(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to cache JIRA %s: %v\n", jiraID, err)
+	}
+	return issue, resp, nil
+}
+
+// fetchIssueUpdated asks JIRA for the issue's updated timestamp only
+func (jc *JiraClient) fetchIssueUpdated(ctx context.Context, jiraID string) (string, error) {
+	var issue jira.Issue
+	if _, err := jc.getRaw(ctx, jiraID, "fields=updated", &issue); err != nil {
+		return "", err
+	}
+	if issue.Fields == nil {
+		return "", fmt.Errorf("JIRA %s has no fields", jiraID)
+	}
+	return time.Time(issue.Fields.Updated).UTC().Format(time.RFC3339Nano), nil
+}
+
+// getRaw requests an issue with the given query and decodes the response into v
+func (jc *JiraClient) getRaw(ctx context.Context, jiraID, query string, v interface{}) (*jira.Response, error) {
+	req, err := jc.client.NewRequest(ctx, http.MethodGet, fmt.Sprintf("rest/api/2/issue/%s?%s", jiraID, query), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := jc.client.Do(req, v)
+	if err != nil {
+		return resp, jira.NewJiraError(resp, err)
+	}
+	return resp, nil
+}
+
+func decodeCachedIssue(entry *cachedIssue) (*jira.Issue, *jira.Response, error) {
+	var issue jira.Issue
+	if err := json.Unmarshal(entry.Issue, &issue); err != nil {
+		return nil, nil, fmt.Errorf("invalid cached issue %s: %v", entry.Key, err)
+	}
+	return &issue, nil, nil
+}
+
+// offlineTransport refuses every request, so --offline can never reach JIRA
+type offlineTransport struct{}
+
+func (offlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, fmt.Errorf("network access to %s is disabled by --offline", req.URL.Host)
+}
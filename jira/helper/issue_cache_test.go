@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeIssueFixture writes a fake JIRA fixture issue whose status and updated timestamp the test controls
+func writeIssueFixture(t *testing.T, dir, key, status, updated string) {
+	t.Helper()
+	issue := fmt.Sprintf(`{
+		"key": %q,
+		"fields": {"status": {"name": %q}, "updated": %q, "project": {"key": "EV"}},
+		"changelog": {"histories": []}
+	}`, key, status, updated)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "issues"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "issues", key+".json"), []byte(issue), 0644))
+}
+
+func TestIssueCache(t *testing.T) {
+	fixtures := t.TempDir()
+	writeIssueFixture(t, fixtures, "EV-1", "In Progress", "2024-01-02T10:00:00.000+0000")
+
+	fake, err := NewFakeJira(os.DirFS(fixtures))
+	require.NoError(t, err)
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	newClient := func(t *testing.T) *JiraClient {
+		client, err := NewJiraClientFromConfig(&AppConfig{
+			JIRAURL:      server.URL,
+			JIRAUsername: "ci@example.com",
+			JIRAToken:    "token",
+			CacheDir:     cacheDir,
+			CacheTTL:     10 * time.Minute,
+		})
+		require.NoError(t, err)
+		client.cache.now = func() time.Time { return now }
+		return client
+	}
+	fetch := func(t *testing.T) (string, []string) {
+		before := len(fake.Requests())
+		response := newClient(t).FetchJiraDetails([]string{"EV-1"})
+		require.Len(t, response.Tasks, 1)
+		return response.Tasks[0].Status, fake.Requests()[before:]
+	}
+
+	t.Run("Miss fetches the full issue", func(t *testing.T) {
+		status, requests := fetch(t)
+		assert.Equal(t, "In Progress", status)
+		assert.Equal(t, []string{"GET /rest/api/2/issue/EV-1?expand=changelog"}, requests)
+	})
+
+	t.Run("Fresh entry skips JIRA", func(t *testing.T) {
+		now = now.Add(5 * time.Minute)
+		status, requests := fetch(t)
+		assert.Equal(t, "In Progress", status)
+		assert.Empty(t, requests)
+	})
+
+	t.Run("Stale unchanged entry is revalidated without the changelog", func(t *testing.T) {
+		now = now.Add(10 * time.Minute)
+		status, requests := fetch(t)
+		assert.Equal(t, "In Progress", status)
+		assert.Equal(t, []string{"GET /rest/api/2/issue/EV-1?fields=updated"}, requests)
+
+		// Revalidation restarts the TTL
+		now = now.Add(5 * time.Minute)
+		_, requests = fetch(t)
+		assert.Empty(t, requests)
+	})
+
+	t.Run("Stale changed entry is refetched", func(t *testing.T) {
+		writeIssueFixture(t, fixtures, "EV-1", "Done", "2024-01-10T12:30:00.000+0000")
+		now = now.Add(time.Hour)
+		status, requests := fetch(t)
+		assert.Equal(t, "Done", status)
+		assert.Equal(t, []string{
+			"GET /rest/api/2/issue/EV-1?fields=updated",
+			"GET /rest/api/2/issue/EV-1?expand=changelog",
+		}, requests)
+	})
+
+	t.Run("Entries are keyed by instance", func(t *testing.T) {
+		cache := NewIssueCache(cacheDir, time.Minute, false)
+		entry, err := cache.load(server.URL, "ev-1")
+		require.NoError(t, err)
+		require.NotNil(t, entry)
+		assert.Equal(t, "EV-1", entry.Key)
+
+		entry, err = cache.load("https://other.atlassian.net", "EV-1")
+		require.NoError(t, err)
+		assert.Nil(t, entry)
+	})
+
+	t.Run("Offline serves only from the cache", func(t *testing.T) {
+		before := len(fake.Requests())
+		client, err := NewJiraClientFromConfig(&AppConfig{
+			JIRAURL:   server.URL,
+			CacheDir:  cacheDir,
+			CacheTTL:  0,
+			CacheOnly: true,
+		})
+		require.NoError(t, err)
+
+		response := client.FetchJiraDetails([]string{"EV-1", "EV-2"})
+		require.Len(t, response.Tasks, 2)
+		assert.Equal(t, "Done", response.Tasks[0].Status)
+		assert.Equal(t, ErrorStatus, response.Tasks[1].Status)
+		assert.Contains(t, response.Tasks[1].Description, "not cached")
+		assert.Len(t, fake.Requests(), before, "--offline must not contact JIRA")
+	})
+
+	t.Run("Damaged entry is refetched", func(t *testing.T) {
+		client := newClient(t)
+		path, err := client.cache.path(server.URL, "EV-1")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, []byte("{"), 0600))
+		status, requests := fetch(t)
+		assert.Equal(t, "Done", status)
+		assert.Equal(t, []string{"GET /rest/api/2/issue/EV-1?expand=changelog"}, requests)
+	})
+
+	t.Run("Keys with path separators are refused", func(t *testing.T) {
+		before := len(fake.Requests())
+		keys := []string{"../../x", `..\x`, "EV-1/../EV-2", ""}
+		response := newClient(t).FetchJiraDetails(keys)
+		require.Len(t, response.Tasks, len(keys))
+		for _, task := range response.Tasks {
+			assert.Equal(t, ErrorStatus, task.Status, task.Key)
+			assert.Contains(t, task.Description, "cannot be used as a cache file name")
+		}
+		assert.Len(t, fake.Requests(), before, "refused keys must not reach JIRA")
+		assert.NoFileExists(t, filepath.Join(cacheDir, "..", "X.json"))
+	})
+}
+
+func TestLoadConfigCache(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv("JIRA_API_TOKEN_FILE", "")
+	t.Setenv("JIRA_CREDENTIAL_HELPER", "")
+	t.Setenv("NETRC", filepath.Join(t.TempDir(), "no-netrc"))
+	t.Setenv("JIRA_URL", "https://example.atlassian.net")
+
+	tests := []struct {
+		name          string
+		flags         *FlagConfig
+		env           map[string]string
+		expectedDir   string
+		expectedTTL   time.Duration
+		errorContains string
+	}{
+		{
+			name:        "Offline needs no credentials",
+			flags:       &FlagConfig{CacheDir: "/tmp/jira-cache", CacheOnly: true},
+			expectedDir: "/tmp/jira-cache",
+			expectedTTL: DefaultCacheTTL,
+		},
+		{
+			name:        "Environment settings",
+			flags:       &FlagConfig{CacheOnly: true},
+			env:         map[string]string{"JIRA_CACHE_DIR": "/var/cache/jira", "JIRA_CACHE_TTL": "2h"},
+			expectedDir: "/var/cache/jira",
+			expectedTTL: 2 * time.Hour,
+		},
+		{
+			name:          "Offline without a cache directory",
+			flags:         &FlagConfig{CacheOnly: true},
+			errorContains: "requires a cache directory",
+		},
+		{
+			name:          "Invalid TTL",
+			flags:         &FlagConfig{CacheDir: "/tmp/jira-cache", CacheTTL: "soon", CacheOnly: true},
+			errorContains: "non-negative duration",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			config, err := LoadConfig(tt.flags, []string{"EV-1"})
+			if tt.errorContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedDir, config.CacheDir)
+			assert.Equal(t, tt.expectedTTL, config.CacheTTL)
+			assert.Equal(t, "https://example.atlassian.net", config.JIRAURL)
+			assert.Empty(t, config.JIRAToken)
+		})
+	}
+}
@@ -3,55 +3,102 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
+	"strings"
 
 	jira "github.com/andygrunwald/go-jira/v2/cloud"
 )
 
 // JiraClient wraps the JIRA client and provides methods for JIRA operations
 type JiraClient struct {
-	client  *jira.Client
-	baseURL string
+	client        *jira.Client
+	baseURL       string
+	trackedFields []string
+	// cache holds fetched issues on disk when set
+	cache *IssueCache
 }
 
-// NewJiraClient creates a new JIRA client with authentication
+// NewJiraClient creates a new JIRA client with authentication from the environment
 func NewJiraClient() (*JiraClient, error) {
-	jiraToken := os.Getenv("JIRA_API_TOKEN")
-	if jiraToken == "" {
-		return nil, &ValidationError{Field: "JIRA_API_TOKEN", Value: "", Err: fmt.Errorf("environment variable not found")}
+	config := &AppConfig{
+		JIRAURL:       os.Getenv("JIRA_URL"),
+		JIRAUsername:  os.Getenv("JIRA_USERNAME"),
+		TrackedFields: parseFieldList(DefaultTrackedFields),
 	}
 
-	jiraURL := os.Getenv("JIRA_URL")
-	if jiraURL == "" {
-		return nil, &ValidationError{Field: "JIRA_URL", Value: "", Err: fmt.Errorf("environment variable not found")}
+	creds, err := resolveCredentials(config.JIRAURL, config.JIRAUsername, credentialSourcesFromEnv(&Profile{}))
+	if err != nil {
+		return nil, err
+	}
+	config.JIRAToken = creds.Token
+	config.JIRAUsername = creds.Username
+
+	return NewJiraClientFromConfig(config)
+}
+
+// NewJiraClientFromConfig creates a JIRA client from loaded configuration.
+// Requests go to config.JIRAURL through config.HTTPClient when set, with basic auth added on top of its transport.
+// With config.CacheOnly, credentials are not needed and every request is refused, so issues come from the cache only.
+func NewJiraClientFromConfig(config *AppConfig) (*JiraClient, error) {
+	if config.CacheOnly {
+		if config.CacheDir == "" {
+			return nil, &ValidationError{Field: "offline", Value: "true", Err: fmt.Errorf("requires a cache directory (--cache-dir or JIRA_CACHE_DIR)")}
+		}
+		if config.JIRAURL == "" {
+			return nil, &ValidationError{Field: "JIRA_URL", Value: "", Err: fmt.Errorf("environment variable is required")}
+		}
+	} else if err := validateJIRAConfig(config); err != nil {
+		return nil, err
 	}
+	baseURL := strings.TrimRight(config.JIRAURL, "/")
 
-	jiraUsername := os.Getenv("JIRA_USERNAME")
-	if jiraUsername == "" {
-		return nil, &ValidationError{Field: "JIRA_USERNAME", Value: "", Err: fmt.Errorf("environment variable not found")}
+	httpClient := &http.Client{}
+	if config.HTTPClient != nil {
+		copied := *config.HTTPClient
+		httpClient = &copied
+	}
+	if config.CacheOnly {
+		httpClient.Transport = offlineTransport{}
 	}
 
 	// Create JIRA client with basic auth transport
 	tp := jira.BasicAuthTransport{
-		Username: jiraUsername,
-		APIToken: jiraToken,
+		Username:  config.JIRAUsername,
+		APIToken:  config.JIRAToken,
+		Transport: httpClient.Transport,
 	}
+	httpClient.Transport = &tp
 
-	client, err := jira.NewClient(jiraURL, tp.Client())
+	client, err := jira.NewClient(baseURL, httpClient)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create JIRA client: %w", err)
+		return nil, fmt.Errorf("failed to create JIRA client: %w", redactError(err))
 	}
 
-	return &JiraClient{
-		client:  client,
-		baseURL: jiraURL,
-	}, nil
+	trackedFields := config.TrackedFields
+	if trackedFields == nil {
+		trackedFields = parseFieldList(DefaultTrackedFields)
+	}
+	if !config.AsOf.IsZero() {
+		trackedFields = withAsOfFields(trackedFields)
+	}
+
+	jiraClient := &JiraClient{
+		client:        client,
+		baseURL:       baseURL,
+		trackedFields: trackedFields,
+	}
+	if config.CacheDir != "" {
+		jiraClient.cache = NewIssueCache(config.CacheDir, config.CacheTTL, config.CacheOnly)
+	}
+	return jiraClient, nil
 }
 
 // FetchJiraDetails fetches JIRA details sequentially
 func (jc *JiraClient) FetchJiraDetails(jiraIDs []string) TransitionCheckResponse {
 	response := TransitionCheckResponse{
-		Tasks: make([]JiraTransitionResult, 0, len(jiraIDs)),
+		SchemaVersion: CurrentSchemaVersion,
+		Tasks:         make([]JiraTransitionResult, 0, len(jiraIDs)),
 	}
 
 	for _, jiraID := range jiraIDs {
@@ -64,7 +111,18 @@ func (jc *JiraClient) FetchJiraDetails(jiraIDs []string) TransitionCheckResponse
 
 // fetchSingleJiraDetail fetches details for a single JIRA ID
 func (jc *JiraClient) fetchSingleJiraDetail(jiraID string) JiraTransitionResult {
-	issue, _, err := jc.client.Issue.Get(context.Background(), jiraID, &jira.GetQueryOptions{Expand: "changelog"})
+	var issue *jira.Issue
+	var resp *jira.Response
+	var err error
+	if jc.cache != nil {
+		issue, resp, err = jc.getIssueCached(context.Background(), jiraID)
+	} else {
+		issue, resp, err = jc.client.Issue.Get(context.Background(), jiraID, &jira.GetQueryOptions{Expand: "changelog"})
+	}
+
+	if err != nil && resp != nil && resp.Response != nil {
+		err = &JiraAPIError{StatusCode: resp.StatusCode, Err: err}
+	}
 
 	if err != nil || issue == nil || issue.Fields == nil {
 		return jc.createErrorResult(jiraID, err)
@@ -77,6 +135,8 @@ func (jc *JiraClient) fetchSingleJiraDetail(jiraID string) JiraTransitionResult
 func (jc *JiraClient) createErrorResult(jiraID string, err error) JiraTransitionResult {
 	errorMsg := "Error: Could not retrieve issue"
 	if err != nil {
+		// Errors can echo request URLs or headers, so keep credentials out of the evidence and logs
+		err = redactError(err)
 		errorMsg = fmt.Sprintf("Error: %v", err)
 		fmt.Fprintf(os.Stderr, "Failed to fetch JIRA %s: %v\n", jiraID, err)
 	}
@@ -94,6 +154,7 @@ func (jc *JiraClient) createErrorResult(jiraID string, err error) JiraTransition
 		Reporter:    "",
 		Priority:    "",
 		Transitions: []Transition{},
+		Error:       classifyError(err),
 	}
 }
 
@@ -106,18 +167,24 @@ func (jc *JiraClient) createSuccessResult(issue *jira.Issue) JiraTransitionResul
 	}
 
 	result := JiraTransitionResult{
-		Key:         issue.Key,
-		Link:        link,
-		Status:      getStatusName(issue.Fields.Status),
-		Description: getDescription(issue.Fields.Description),
-		Type:        getIssueTypeName(issue.Fields.Type),
-		Project:     getProjectKey(issue.Fields.Project),
-		Created:     getTimeAsString(issue.Fields.Created),
-		Updated:     getTimeAsString(issue.Fields.Updated),
-		Assignee:    getAssignee(issue.Fields.Assignee),
-		Reporter:    getReporterName(issue.Fields.Reporter),
-		Priority:    getPriorityName(issue.Fields.Priority),
-		Transitions: jc.extractTransitions(issue),
+		Key:                 issue.Key,
+		Link:                link,
+		Summary:             issue.Fields.Summary,
+		Status:              getStatusName(issue.Fields.Status),
+		Description:         getDescription(issue.Fields.Description),
+		DescriptionMarkdown: getDescriptionMarkdown(issue.Fields.Description),
+		Type:                getIssueTypeName(issue.Fields.Type),
+		Project:             getProjectKey(issue.Fields.Project),
+		Created:             getTimeAsString(issue.Fields.Created),
+		Updated:             getTimeAsString(issue.Fields.Updated),
+		Assignee:            getAssignee(issue.Fields.Assignee),
+		Reporter:            getReporterName(issue.Fields.Reporter),
+		Priority:            getPriorityName(issue.Fields.Priority),
+		Labels:              issue.Fields.Labels,
+		Components:          getComponentNames(issue.Fields.Components),
+		FixVersions:         getFixVersionNames(issue.Fields.FixVersions),
+		Transitions:         jc.extractTransitions(issue),
+		FieldChanges:        jc.extractFieldChanges(issue),
 	}
 
 	return result
@@ -148,3 +215,40 @@ func (jc *JiraClient) extractTransitions(issue *jira.Issue) []Transition {
 
 	return transitions
 }
+
+// extractFieldChanges extracts changes to the tracked non-status fields from issue changelog
+func (jc *JiraClient) extractFieldChanges(issue *jira.Issue) []FieldChange {
+	var changes []FieldChange
+
+	if issue.Changelog == nil || len(jc.trackedFields) == 0 {
+		return changes
+	}
+
+	for _, history := range issue.Changelog.Histories {
+		for _, item := range history.Items {
+			if !jc.isTrackedField(item.Field) {
+				continue
+			}
+			changes = append(changes, FieldChange{
+				Field:       item.Field,
+				FromValue:   item.FromString,
+				ToValue:     item.ToString,
+				Author:      history.Author.DisplayName,
+				AuthorEmail: history.Author.EmailAddress,
+				ChangeTime:  history.Created,
+			})
+		}
+	}
+
+	return changes
+}
+
+// isTrackedField reports whether a changelog field is configured for tracking (case-insensitive)
+func (jc *JiraClient) isTrackedField(field string) bool {
+	for _, tracked := range jc.trackedFields {
+		if strings.EqualFold(tracked, field) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,14 +1,19 @@
 package main
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	jira "github.com/andygrunwald/go-jira/v2/cloud"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewJiraClient(t *testing.T) {
@@ -111,18 +116,166 @@ func TestNewJiraClient(t *testing.T) {
 	}
 }
 
-func TestJiraClient_FetchJiraDetails(t *testing.T) {
-	// This test demonstrates the structure of FetchJiraDetails
-	// Actual testing would require mocking the JIRA API client
-	// which is complex without dependency injection
+// newTestJiraServer serves canned issues for the given keys and 404 for anything else,
+// recording the Authorization header of each request
+func newTestJiraServer(t *testing.T, issues map[string]string) (*httptest.Server, *[]string) {
+	t.Helper()
+	var authHeaders []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+		key := strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/")
+		body, ok := issues[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errorMessages":["Issue does not exist or you do not have permission to see it."]}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &authHeaders
+}
+
+const testIssueJSON = `{
+	"key": "EV-1",
+	"fields": {
+		"status": {"name": "Done"},
+		"description": "Fetched through the injected client",
+		"issuetype": {"name": "Task"},
+		"project": {"key": "EV"},
+		"reporter": {"displayName": "Jane Smith"},
+		"priority": {"name": "High"}
+	},
+	"changelog": {"histories": [{
+		"created": "2024-01-02T10:00:00.000+0000",
+		"author": {"displayName": "User One", "emailAddress": "user1@example.com"},
+		"items": [{"field": "status", "fromString": "To Do", "toString": "Done"}]
+	}]}
+}`
+
+func TestNewJiraClientFromConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *AppConfig
+		expectField string
+	}{
+		{
+			name:        "Missing token",
+			config:      &AppConfig{JIRAURL: "https://example.atlassian.net", JIRAUsername: "user@example.com"},
+			expectField: "JIRA_API_TOKEN",
+		},
+		{
+			name:        "Missing URL",
+			config:      &AppConfig{JIRAToken: "token", JIRAUsername: "user@example.com"},
+			expectField: "JIRA_URL",
+		},
+		{
+			name:   "Valid configuration",
+			config: &AppConfig{JIRAToken: "token", JIRAURL: "https://example.atlassian.net/", JIRAUsername: "user@example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewJiraClientFromConfig(tt.config)
+			if tt.expectField != "" {
+				var validationErr *ValidationError
+				require.True(t, errors.As(err, &validationErr))
+				assert.Equal(t, tt.expectField, validationErr.Field)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "https://example.atlassian.net", client.baseURL)
+			assert.Equal(t, parseFieldList(DefaultTrackedFields), client.trackedFields)
+		})
+	}
+}
 
-	// Test that response structure is correct
-	response := TransitionCheckResponse{
-		Tasks: make([]JiraTransitionResult, 0, 3),
+func TestJiraClient_FetchJiraDetails(t *testing.T) {
+	server, authHeaders := newTestJiraServer(t, map[string]string{"EV-1": testIssueJSON})
+
+	// The injected client's settings are kept while authentication is layered on its transport
+	config := &AppConfig{
+		JIRAURL:       server.URL,
+		JIRAUsername:  "user@example.com",
+		JIRAToken:     "test-token",
+		TrackedFields: []string{},
+		HTTPClient:    &http.Client{Timeout: 5 * time.Second},
+	}
+	client, err := NewJiraClientFromConfig(config)
+	require.NoError(t, err)
+	assert.Nil(t, config.HTTPClient.Transport, "injected client must not be modified")
+
+	response := client.FetchJiraDetails([]string{"EV-1", "EV-404"})
+	require.Len(t, response.Tasks, 2)
+	assert.Equal(t, CurrentSchemaVersion, response.SchemaVersion)
+
+	task := response.Tasks[0]
+	assert.Equal(t, "EV-1", task.Key)
+	assert.Equal(t, "Done", task.Status)
+	assert.Equal(t, server.URL+"/browse/EV-1", task.Link)
+	assert.Equal(t, "Fetched through the injected client", task.Description)
+	require.Len(t, task.Transitions, 1)
+	assert.Equal(t, "To Do", task.Transitions[0].FromStatus)
+
+	missing := response.Tasks[1]
+	assert.Equal(t, ErrorStatus, missing.Status)
+	require.NotNil(t, missing.Error)
+	assert.Equal(t, ErrorCodeNotFound, missing.Error.Code)
+	assert.Equal(t, http.StatusNotFound, missing.Error.HTTPStatus)
+
+	expectedAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte("user@example.com:test-token"))
+	for _, header := range *authHeaders {
+		assert.Equal(t, expectedAuth, header)
 	}
+}
 
-	assert.NotNil(t, response.Tasks)
-	assert.Equal(t, 0, len(response.Tasks))
+func TestJiraClient_FetchJiraDetailsFieldChanges(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/2/issue/EV-1", r.URL.Path)
+		assert.Equal(t, "changelog", r.URL.Query().Get("expand"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{
+			"key": "EV-1",
+			"fields": {
+				"status": {"name": "In Progress"},
+				"issuetype": {"name": "Story"},
+				"project": {"key": "EV"},
+				"assignee": {"displayName": "Jane Smith"}
+			},
+			"changelog": {"histories": [{
+				"created": "2025-01-02T10:00:00.000+0000",
+				"author": {"displayName": "John Doe", "emailAddress": "john@example.com"},
+				"items": [
+					{"field": "status", "fromString": "To Do", "toString": "In Progress"},
+					{"field": "assignee", "fromString": "", "toString": "Jane Smith"},
+					{"field": "labels", "fromString": "", "toString": "backend"}
+				]
+			}]}
+		}`)
+	}))
+	defer server.Close()
+
+	t.Setenv("JIRA_URL", server.URL)
+	t.Setenv("JIRA_USERNAME", "ci@example.com")
+	t.Setenv("JIRA_API_TOKEN", "token")
+
+	client, err := NewJiraClient()
+	require.NoError(t, err)
+
+	response := client.FetchJiraDetails([]string{"EV-1"})
+	require.Len(t, response.Tasks, 1)
+	task := response.Tasks[0]
+	assert.Equal(t, "In Progress", task.Status)
+	require.Len(t, task.Transitions, 1)
+	assert.Equal(t, []FieldChange{{
+		Field:       "assignee",
+		ToValue:     "Jane Smith",
+		Author:      "John Doe",
+		AuthorEmail: "john@example.com",
+		ChangeTime:  "2025-01-02T10:00:00.000+0000",
+	}}, task.FieldChanges)
 }
 
 func TestJiraClient_createErrorResult(t *testing.T) {
@@ -196,6 +349,8 @@ func TestJiraClient_createErrorResult(t *testing.T) {
 			assert.Equal(t, "", result.Reporter)
 			assert.Equal(t, "", result.Priority)
 			assert.Empty(t, result.Transitions)
+			assert.NotNil(t, result.Error)
+			assert.Equal(t, ErrorCodeUnknown, result.Error.Code)
 
 			// Verify stderr output if captured
 			if tt.captureStderr && tt.err != nil {
@@ -218,6 +373,7 @@ func TestJiraClient_createSuccessResult(t *testing.T) {
 	issue := &jira.Issue{
 		Key: "EV-123",
 		Fields: &jira.IssueFields{
+			Summary: "Test summary",
 			Status: &jira.Status{
 				Name: "In Progress",
 			},
@@ -239,6 +395,8 @@ func TestJiraClient_createSuccessResult(t *testing.T) {
 			Priority: &jira.Priority{
 				Name: "High",
 			},
+			Labels:     []string{"security"},
+			Components: []*jira.Component{{Name: "pipeline"}},
 		},
 		Changelog: &jira.Changelog{
 			Histories: []jira.ChangelogHistory{
@@ -275,6 +433,9 @@ func TestJiraClient_createSuccessResult(t *testing.T) {
 	assert.Equal(t, assigneeName, *result.Assignee)
 	assert.Equal(t, "Jane Smith", result.Reporter)
 	assert.Equal(t, "High", result.Priority)
+	assert.Equal(t, "Test summary", result.Summary)
+	assert.Equal(t, []string{"security"}, result.Labels)
+	assert.Equal(t, []string{"pipeline"}, result.Components)
 	assert.Len(t, result.Transitions, 1)
 	assert.Equal(t, "To Do", result.Transitions[0].FromStatus)
 	assert.Equal(t, "In Progress", result.Transitions[0].ToStatus)
@@ -425,3 +586,98 @@ func TestJiraClient_fetchSingleJiraDetail(t *testing.T) {
 	assert.Equal(t, ErrorType, errorResult.Type)
 	assert.Contains(t, errorResult.Description, "Error:")
 }
+
+func TestJiraClient_extractFieldChanges(t *testing.T) {
+	issue := &jira.Issue{
+		Changelog: &jira.Changelog{
+			Histories: []jira.ChangelogHistory{
+				{
+					Created: "2023-12-14T10:00:00.000+0000",
+					Author: jira.User{
+						DisplayName:  "User One",
+						EmailAddress: "user1@example.com",
+					},
+					Items: []jira.ChangelogItems{
+						{Field: "status", FromString: "To Do", ToString: "In Progress"},
+						{Field: "priority", FromString: "Low", ToString: "High"},
+					},
+				},
+				{
+					Created: "2023-12-15T10:00:00.000+0000",
+					Author: jira.User{
+						DisplayName:  "User Two",
+						EmailAddress: "user2@example.com",
+					},
+					Items: []jira.ChangelogItems{
+						{Field: "assignee", FromString: "Alice", ToString: "Bob"},
+						{Field: "Fix Version", FromString: "", ToString: "1.2.0"},
+						{Field: "labels", FromString: "", ToString: "backend"},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		trackedFields  []string
+		asOf           time.Time
+		expectedFields []string
+	}{
+		{
+			name:           "Default tracked fields",
+			trackedFields:  parseFieldList(DefaultTrackedFields),
+			expectedFields: []string{"priority", "assignee", "Fix Version"},
+		},
+		{
+			name:           "Case-insensitive custom field list",
+			trackedFields:  []string{"LABELS", "Assignee"},
+			expectedFields: []string{"assignee", "labels"},
+		},
+		{
+			name:           "No tracked fields",
+			trackedFields:  []string{},
+			expectedFields: nil,
+		},
+		{
+			name:           "Fields rebuilt by --as-of are always extracted",
+			trackedFields:  []string{"Sprint"},
+			asOf:           time.Date(2023, 12, 14, 12, 0, 0, 0, time.UTC),
+			expectedFields: []string{"priority", "assignee", "Fix Version", "labels"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewJiraClientFromConfig(&AppConfig{
+				JIRAURL:       "https://example.atlassian.net",
+				JIRAUsername:  "ci@example.com",
+				JIRAToken:     "token",
+				TrackedFields: tt.trackedFields,
+				AsOf:          tt.asOf,
+			})
+			require.NoError(t, err)
+
+			changes := client.extractFieldChanges(issue)
+
+			var fields []string
+			for _, change := range changes {
+				fields = append(fields, change.Field)
+			}
+			assert.Equal(t, tt.expectedFields, fields)
+		})
+	}
+
+	// Verify change details
+	client := &JiraClient{trackedFields: []string{"assignee"}}
+	changes := client.extractFieldChanges(issue)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, "Alice", changes[0].FromValue)
+	assert.Equal(t, "Bob", changes[0].ToValue)
+	assert.Equal(t, "User Two", changes[0].Author)
+	assert.Equal(t, "user2@example.com", changes[0].AuthorEmail)
+	assert.Equal(t, "2023-12-15T10:00:00.000+0000", changes[0].ChangeTime)
+
+	// Issue without changelog
+	assert.Empty(t, client.extractFieldChanges(&jira.Issue{}))
+}
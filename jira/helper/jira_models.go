@@ -12,11 +12,14 @@ const (
     its structure should be:
 
     {
+        "schema_version": "1.4.0",
         "tasks": [
             {
                 "key": "EV-1",
+                "summary": "<issue summary>",
                 "status": "QA in Progress",
                 "description": "<description text>",
+                "description_markdown": "<description rendered as markdown, only for ADF descriptions>",
                 "type": "Task",
                 "project": "EV",
                 "created": "2020-01-01T12:11:56.063+0530",
@@ -24,6 +27,8 @@ const (
                 "assignee": "<assignee name>",
                 "reporter": "<reporter name>",
                 "priority": "Medium",
+                "labels": ["security"],
+                "components": ["pipeline"],
                 "transitions": [
                     {
                         "from_status": "To Do",
@@ -32,7 +37,30 @@ const (
                         "author_user_name": "<author email>",
                         "transition_time": "2020-07-28T16:39:54.620+0530"
                     }
-                ]
+                ],
+                "field_changes": [
+                    {
+                        "field": "assignee",
+                        "from_value": "<previous assignee>",
+                        "to_value": "<new assignee>",
+                        "author": "<author name>",
+                        "author_user_name": "<author email>",
+                        "change_time": "2020-07-29T09:12:00.000+0530"
+                    }
+                ],
+                "metrics": {
+                    "time_in_status": [
+                        { "status": "To Do", "hours": 4.5 },
+                        { "status": "In Progress", "hours": 30.25 }
+                    ],
+                    "lead_time_hours": 34.75,
+                    "cycle_time_hours": 30.25
+                },
+                "field_values": {
+                    "assignee": "<assignee at the --as-of instant>",
+                    "Fix Version": "1.2.0"
+                },
+                "sources": ["btcwallet", "btcwallet-ui"]
             },
             {
                 "key": "EV-2",
@@ -45,32 +73,84 @@ const (
                 "assignee": null,
                 "reporter": "",
                 "priority": "",
-                "transitions": []
+                "transitions": [],
+                "error": {
+                    "code": "not_found",
+                    "http_status": 404,
+                    "retryable": false,
+                    "message": "JIRA API returned status 404: ..."
+                }
             }
-        ]
+        ],
+        "aggregates": {
+            "lead_time": { "count": 1, "mean_hours": 34.75, "median_hours": 34.75, "p90_hours": 34.75 },
+            "cycle_time": { "count": 1, "mean_hours": 30.25, "median_hours": 30.25, "p90_hours": 30.25 }
+        },
+        "as_of": "2020-07-30T00:00:00.000+0000",
+        "update": {
+            "added": ["EV-2"],
+            "removed": [],
+            "changed": [],
+            "unchanged": ["EV-1"]
+        }
     }
 
+   "as_of", "field_values" and the reconstructed status/assignee/priority are only present when --as-of is used:
+   the tasks then describe the state at that instant, and history recorded after it is dropped.
+   "sources" is only present on tasks of evidence merged from several builds by the merge command.
+   "update" is only present when the evidence was built incrementally from a previous file by the update command.
+
+   The format is versioned by schema_version (see CurrentSchemaVersion) and published as a JSON Schema
+   generated from these structs (schema/transition_check_response.schema.json, printed by `./main schema`).
+
    notice that the calling client should first check that return value was 0 before using the response JSON,
    otherwise the response is an error message which cannot be parsed
 */
 
 type TransitionCheckResponse struct {
-	Tasks []JiraTransitionResult `json:"tasks"`
+	SchemaVersion string                 `json:"schema_version"`
+	Tasks         []JiraTransitionResult `json:"tasks"`
+	Aggregates    *ReleaseMetrics        `json:"aggregates,omitempty"`
+	AsOf          string                 `json:"as_of,omitempty"`
+	Update        *EvidenceUpdate        `json:"update,omitempty"`
+}
+
+// EvidenceUpdate records how incrementally updated evidence differs from the previous file it was built from
+type EvidenceUpdate struct {
+	// Added tickets were not in the previous evidence
+	Added []string `json:"added"`
+	// Removed tickets were in the previous evidence but are no longer referenced
+	Removed []string `json:"removed"`
+	// Changed tickets were refetched because JIRA updated them since, or because they could not be fetched before
+	Changed []string `json:"changed"`
+	// Unchanged tickets were taken over from the previous evidence without refetching
+	Unchanged []string `json:"unchanged"`
 }
 
 type JiraTransitionResult struct {
-	Key         string       `json:"key"`
-	Link        string       `json:"link,omitempty"`
-	Status      string       `json:"status"`
-	Description string       `json:"description"`
-	Type        string       `json:"type"`
-	Project     string       `json:"project"`
-	Created     string       `json:"created"`
-	Updated     string       `json:"updated"`
-	Assignee    *string      `json:"assignee"`
-	Reporter    string       `json:"reporter"`
-	Priority    string       `json:"priority"`
-	Transitions []Transition `json:"transitions"`
+	Key         string `json:"key"`
+	Link        string `json:"link,omitempty"`
+	Summary     string `json:"summary,omitempty"`
+	Status      string `json:"status"`
+	Description string `json:"description"`
+	// DescriptionMarkdown holds the ADF description rendered as Markdown; empty for plain-text descriptions
+	DescriptionMarkdown string            `json:"description_markdown,omitempty"`
+	Type                string            `json:"type"`
+	Project             string            `json:"project"`
+	Created             string            `json:"created"`
+	Updated             string            `json:"updated"`
+	Assignee            *string           `json:"assignee"`
+	Reporter            string            `json:"reporter"`
+	Priority            string            `json:"priority"`
+	Labels              []string          `json:"labels,omitempty"`
+	Components          []string          `json:"components,omitempty"`
+	FixVersions         []string          `json:"fix_versions,omitempty"`
+	Transitions         []Transition      `json:"transitions"`
+	FieldChanges        []FieldChange     `json:"field_changes,omitempty"`
+	Metrics             *TaskMetrics      `json:"metrics,omitempty"`
+	FieldValues         map[string]string `json:"field_values,omitempty"`
+	Error               *TaskError        `json:"error,omitempty"`
+	Sources             []string          `json:"sources,omitempty"`
 }
 
 type Transition struct {
@@ -80,3 +160,47 @@ type Transition struct {
 	AuthorEmail    string `json:"author_user_name"`
 	TransitionTime string `json:"transition_time"`
 }
+
+type FieldChange struct {
+	Field       string `json:"field"`
+	FromValue   string `json:"from_value"`
+	ToValue     string `json:"to_value"`
+	Author      string `json:"author"`
+	AuthorEmail string `json:"author_user_name"`
+	ChangeTime  string `json:"change_time"`
+}
+
+// TaskMetrics holds flow metrics derived from a task's transitions
+type TaskMetrics struct {
+	TimeInStatus   []StatusDuration `json:"time_in_status"`
+	LeadTimeHours  *float64         `json:"lead_time_hours,omitempty"`
+	CycleTimeHours *float64         `json:"cycle_time_hours,omitempty"`
+}
+
+type StatusDuration struct {
+	Status string  `json:"status"`
+	Hours  float64 `json:"hours"`
+}
+
+// ReleaseMetrics aggregates task metrics across all tasks in the response
+type ReleaseMetrics struct {
+	LeadTime  DurationStats `json:"lead_time"`
+	CycleTime DurationStats `json:"cycle_time"`
+}
+
+type DurationStats struct {
+	Count       int     `json:"count"`
+	MeanHours   float64 `json:"mean_hours"`
+	MedianHours float64 `json:"median_hours"`
+	P90Hours    float64 `json:"p90_hours"`
+}
+
+// TaskError describes why a task could not be fetched.
+// Code is one of: unauthorized, permission_denied, not_found, rate_limited, bad_request,
+// server_error, network_error, unknown.
+type TaskError struct {
+	Code       string `json:"code"`
+	HTTPStatus int    `json:"http_status,omitempty"`
+	Retryable  bool   `json:"retryable"`
+	Message    string `json:"message"`
+}
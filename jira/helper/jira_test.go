@@ -86,7 +86,7 @@ func TestGetDescription(t *testing.T) {
 					},
 				},
 			},
-			expected: "First paragraphSecond paragraph",
+			expected: "First paragraph\n\nSecond paragraph",
 		},
 		{
 			name:     "invalid ADF format",
@@ -103,8 +103,8 @@ func TestGetDescription(t *testing.T) {
 	}
 }
 
-// Test extractTextFromADFNode function
-func TestExtractTextFromADFNode(t *testing.T) {
+// Test plain-text rendering of single ADF nodes
+func TestRenderADFPlainTextNode(t *testing.T) {
 	tests := []struct {
 		name     string
 		input    interface{}
@@ -152,7 +152,7 @@ func TestExtractTextFromADFNode(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := extractTextFromADFNode(tt.input)
+			result := renderADF(tt.input, ADFPlainText)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
@@ -243,6 +243,15 @@ func TestFieldExtractors(t *testing.T) {
 		assert.Equal(t, "High", getPriorityName(priority))
 	})
 
+	t.Run("getComponentNames", func(t *testing.T) {
+		// Test no components
+		assert.Nil(t, getComponentNames(nil))
+
+		// Test valid components, skipping unnamed ones
+		components := []*jira.Component{{Name: "pipeline"}, nil, {ID: "10"}, {Name: "reports"}}
+		assert.Equal(t, []string{"pipeline", "reports"}, getComponentNames(components))
+	})
+
 	t.Run("getAssignee", func(t *testing.T) {
 		// Test nil assignee
 		assert.Nil(t, getAssignee(nil))
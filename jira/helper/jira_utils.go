@@ -3,7 +3,6 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"strings"
 	"time"
 
 	jira "github.com/andygrunwald/go-jira/v2/cloud"
@@ -40,6 +39,26 @@ func getPriorityName(priority *jira.Priority) string {
 	return priority.Name
 }
 
+func getComponentNames(components []*jira.Component) []string {
+	var names []string
+	for _, component := range components {
+		if component != nil && component.Name != "" {
+			names = append(names, component.Name)
+		}
+	}
+	return names
+}
+
+func getFixVersionNames(versions []*jira.FixVersion) []string {
+	var names []string
+	for _, version := range versions {
+		if version != nil && version.Name != "" {
+			names = append(names, version.Name)
+		}
+	}
+	return names
+}
+
 func getAssignee(assignee *jira.User) *string {
 	if assignee == nil {
 		return nil
@@ -76,7 +95,7 @@ func getTimeAsString(timeField interface{}) string {
 	}
 }
 
-// getDescription extracts description text from JIRA description field
+// getDescription extracts plain description text from JIRA description field
 func getDescription(desc interface{}) string {
 	if desc == nil {
 		return ""
@@ -89,57 +108,28 @@ func getDescription(desc interface{}) string {
 		return fmt.Sprintf("%v", desc)
 	}
 
-	content, ok := descMap["content"].([]interface{})
-	if !ok {
+	if _, ok := descMap["content"].([]interface{}); !ok {
 		return fmt.Sprintf("%v", desc)
 	}
 
-	var result strings.Builder
-	for _, item := range content {
-		text := extractTextFromADFNode(item)
-		if text != "" {
-			result.WriteString(text)
-		}
-	}
-
-	if result.Len() == 0 {
+	text := renderADF(descMap, ADFPlainText)
+	if text == "" {
 		return fmt.Sprintf("%v", desc)
 	}
-	return result.String()
+	return text
 }
 
-// extractTextFromADFNode extracts text from an ADF node (paragraph, text, etc.)
-func extractTextFromADFNode(node interface{}) string {
-	nodeMap, ok := node.(map[string]interface{})
+// getDescriptionMarkdown renders an ADF description as Markdown.
+// Returns an empty string when the description is not ADF, since plain text needs no separate rendering.
+func getDescriptionMarkdown(desc interface{}) string {
+	descMap, ok := desc.(map[string]interface{})
 	if !ok {
 		return ""
 	}
 
-	nodeType, _ := nodeMap["type"].(string)
-
-	switch nodeType {
-	case "paragraph":
-		// Extract text from paragraph's content
-		content, ok := nodeMap["content"].([]interface{})
-		if !ok {
-			return ""
-		}
-
-		var texts []string
-		for _, item := range content {
-			if text := extractTextFromADFNode(item); text != "" {
-				texts = append(texts, text)
-			}
-		}
-		return strings.Join(texts, "")
-
-	case "text":
-		// Direct text node
-		text, _ := nodeMap["text"].(string)
-		return text
-
-	default:
-		// Handle other node types if needed
+	if _, ok := descMap["content"].([]interface{}); !ok {
 		return ""
 	}
+
+	return renderADF(descMap, ADFMarkdown)
 }
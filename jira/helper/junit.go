@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// JUnitSuiteName is the test suite gate results are reported under
+const JUnitSuiteName = "jira-release-gate"
+
+// junitEvidenceCase is the test case of violations that are not tied to a single ticket
+const junitEvidenceCase = "evidence"
+
+// junitTestSuites is the root element of a JUnit XML report
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Errors     int              `xml:"errors,attr"`
+	Skipped    int              `xml:"skipped,attr"`
+	Properties *junitProperties `xml:"properties"`
+	TestCases  []junitTestCase  `xml:"testcase"`
+}
+
+type junitProperties struct {
+	Property []junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// junitTestCase is one ticket; it fails once per violated rule
+type junitTestCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Failures  []junitFailure `xml:"failure"`
+	Skipped   *junitSkipped  `xml:"skipped"`
+	SystemOut string         `xml:"system-out,omitempty"`
+}
+
+// junitFailure is a violated rule; Type is the stable rule ID
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// newJUnitReport converts gate results into a JUnit report with one test case per ticket.
+// Tickets that could not be fetched but were let through with --allow-errors are reported as skipped,
+// and violations of the evidence as a whole fail an extra "evidence" test case.
+func newJUnitReport(report *GateReport, response TransitionCheckResponse, inputFile string) junitTestSuites {
+	suite := junitTestSuite{Name: JUnitSuiteName}
+	var properties []junitProperty
+	for _, property := range []junitProperty{
+		{Name: "evidence", Value: inputFile},
+		{Name: "schema_version", Value: response.SchemaVersion},
+		{Name: "as_of", Value: response.AsOf},
+	} {
+		if property.Value != "" {
+			properties = append(properties, property)
+		}
+	}
+	if len(properties) > 0 {
+		suite.Properties = &junitProperties{Property: properties}
+	}
+
+	for _, result := range report.Results {
+		task := result.Task
+		testCase := junitTestCase{
+			Name:      task.Key,
+			ClassName: junitClassName(task),
+			SystemOut: fmt.Sprintf("status: %s", task.Status),
+		}
+		for _, violation := range result.Violations {
+			testCase.Failures = append(testCase.Failures, junitViolation(violation))
+		}
+		if result.Passed() && task.Status == ErrorStatus {
+			testCase.Skipped = &junitSkipped{Message: fmt.Sprintf("could not be fetched: %s", taskErrorMessage(task))}
+		}
+		suite.addTestCase(testCase)
+	}
+
+	if len(report.Violations) > 0 {
+		testCase := junitTestCase{Name: junitEvidenceCase, ClassName: "jira"}
+		for _, violation := range report.Violations {
+			testCase.Failures = append(testCase.Failures, junitViolation(violation))
+		}
+		suite.addTestCase(testCase)
+	}
+
+	return junitTestSuites{
+		Name:     JUnitSuiteName,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Suites:   []junitTestSuite{suite},
+	}
+}
+
+// addTestCase appends a test case and counts it
+func (s *junitTestSuite) addTestCase(testCase junitTestCase) {
+	s.Tests++
+	if len(testCase.Failures) > 0 {
+		s.Failures++
+	} else if testCase.Skipped != nil {
+		s.Skipped++
+	}
+	s.TestCases = append(s.TestCases, testCase)
+}
+
+// junitClassName groups tickets by project, so test reporters show one class per JIRA project.
+// Tickets that could not be fetched have no project and are grouped by their key prefix.
+func junitClassName(task JiraTransitionResult) string {
+	project := task.Project
+	if project == "" {
+		project, _, _ = strings.Cut(task.Key, "-")
+	}
+	if project == "" {
+		return "jira"
+	}
+	return "jira." + project
+}
+
+func junitViolation(violation PolicyViolation) junitFailure {
+	text := violation.Message
+	if description, ok := policyRuleDescriptions[violation.RuleID]; ok {
+		text = fmt.Sprintf("%s\n%s: %s", violation.Message, violation.RuleID, description)
+	}
+	return junitFailure{Message: violation.Message, Type: violation.RuleID, Text: text}
+}
+
+// writeJUnitReport writes gate results as JUnit XML
+func writeJUnitReport(report *GateReport, response TransitionCheckResponse, inputFile, outputFile string) error {
+	data, err := xml.MarshalIndent(newJUnitReport(report, response, inputFile), "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling JUnit XML: %v", err)
+	}
+	data = append([]byte(xml.Header), append(data, '\n')...)
+	if err := writeToFile(outputFile, data); err != nil {
+		return fmt.Errorf("error writing to file: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJUnitReport(t *testing.T) {
+	response := TransitionCheckResponse{
+		SchemaVersion: CurrentSchemaVersion,
+		Tasks: []JiraTransitionResult{
+			{Key: "EV-1", Project: "EV", Status: "Done"},
+			{Key: "EV-2", Project: "EV", Status: "In Review"},
+			{Key: "OPS-3", Status: ErrorStatus, Error: &TaskError{Code: ErrorCodeNotFound, Message: "Issue does not exist"}},
+		},
+	}
+	policy := GatePolicy{AllowedStatuses: []string{"Done"}}
+
+	suites := newJUnitReport(evaluatePolicy(response, policy), response, "evidence.json")
+	assert.Equal(t, 3, suites.Tests)
+	assert.Equal(t, 2, suites.Failures)
+	require.Len(t, suites.Suites, 1)
+
+	suite := suites.Suites[0]
+	assert.Equal(t, JUnitSuiteName, suite.Name)
+	require.NotNil(t, suite.Properties)
+	assert.Equal(t, []junitProperty{{Name: "evidence", Value: "evidence.json"}, {Name: "schema_version", Value: CurrentSchemaVersion}},
+		suite.Properties.Property)
+	require.Len(t, suite.TestCases, 3)
+
+	passed := suite.TestCases[0]
+	assert.Equal(t, "EV-1", passed.Name)
+	assert.Equal(t, "jira.EV", passed.ClassName)
+	assert.Empty(t, passed.Failures)
+	assert.Equal(t, "status: Done", passed.SystemOut)
+
+	notDone := suite.TestCases[1]
+	require.Len(t, notDone.Failures, 1)
+	assert.Equal(t, RuleStatusNotAllowed, notDone.Failures[0].Type)
+	assert.Equal(t, "EV-2 is 'In Review', expected one of: Done", notDone.Failures[0].Message)
+	assert.Contains(t, notDone.Failures[0].Text, policyRuleDescriptions[RuleStatusNotAllowed])
+
+	// Tickets that could not be fetched have no project, so they are grouped by key prefix
+	fetchError := suite.TestCases[2]
+	assert.Equal(t, "jira.OPS", fetchError.ClassName)
+	require.Len(t, fetchError.Failures, 1)
+	assert.Equal(t, RuleFetchError, fetchError.Failures[0].Type)
+
+	t.Run("Allowed fetch errors are skipped", func(t *testing.T) {
+		policy := GatePolicy{AllowedStatuses: []string{"Done"}, AllowErrors: true}
+		suites := newJUnitReport(evaluatePolicy(response, policy), response, "")
+		assert.Equal(t, 1, suites.Failures)
+		assert.Equal(t, 1, suites.Skipped)
+		require.NotNil(t, suites.Suites[0].Properties)
+		assert.Equal(t, []junitProperty{{Name: "schema_version", Value: CurrentSchemaVersion}}, suites.Suites[0].Properties.Property)
+
+		skipped := suites.Suites[0].TestCases[2]
+		assert.Empty(t, skipped.Failures)
+		require.NotNil(t, skipped.Skipped)
+		assert.Equal(t, "could not be fetched: Issue does not exist (not_found)", skipped.Skipped.Message)
+	})
+
+	t.Run("Evidence without tickets", func(t *testing.T) {
+		empty := TransitionCheckResponse{Tasks: []JiraTransitionResult{}}
+		suites := newJUnitReport(evaluatePolicy(empty, GatePolicy{RequireTasks: true}), empty, "")
+		assert.Equal(t, 1, suites.Tests)
+		assert.Equal(t, 1, suites.Failures)
+		testCase := suites.Suites[0].TestCases[0]
+		assert.Equal(t, junitEvidenceCase, testCase.Name)
+		require.Len(t, testCase.Failures, 1)
+		assert.Equal(t, RuleNoTasks, testCase.Failures[0].Type)
+	})
+}
+
+func TestWriteJUnitReport(t *testing.T) {
+	response := TransitionCheckResponse{Tasks: []JiraTransitionResult{
+		{Key: "EV-1", Project: "EV", Status: "Done"},
+		{Key: "EV-2", Project: "EV", Status: "To Do & <blocked>"},
+	}}
+	report := evaluatePolicy(response, GatePolicy{AllowedStatuses: []string{"Done"}})
+
+	outputFile := filepath.Join(t.TempDir(), "gate.xml")
+	require.NoError(t, writeJUnitReport(report, response, "", outputFile))
+
+	data, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="jira-release-gate" tests="2" failures="1" skipped="0">
+  <testsuite name="jira-release-gate" tests="2" failures="1" errors="0" skipped="0">
+    <testcase name="EV-1" classname="jira.EV">
+      <system-out>status: Done</system-out>
+    </testcase>
+    <testcase name="EV-2" classname="jira.EV">
+      <failure message="EV-2 is &#39;To Do &amp; &lt;blocked&gt;&#39;, expected one of: Done" type="jira/status-not-allowed">EV-2 is &#39;To Do &amp; &lt;blocked&gt;&#39;, expected one of: Done&#xA;jira/status-not-allowed: The ticket is not in an allowed status</failure>
+      <system-out>status: To Do &amp; &lt;blocked&gt;</system-out>
+    </testcase>
+  </testsuite>
+</testsuites>
+`, string(data))
+
+	// The report parses back
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal(data, &suites))
+	assert.Equal(t, 2, suites.Tests)
+}
@@ -1,12 +1,19 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 )
 
 func main() {
-	// Parse command line flags
+	// Run an explicit subcommand (fetch, extract, report, gate, ...) when one is given
+	if handled, err := dispatchCommand(os.Args[1:]); handled {
+		exitOnError(err)
+		return
+	}
+
+	// Compatibility shim: invocations without a subcommand keep the original flag-driven behavior
 	flags, args := ParseFlags()
 
 	// Handle help flags
@@ -18,14 +25,24 @@ func main() {
 	// Load configuration
 	config, err := LoadConfig(flags, args)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", redact(err.Error()))
 		DisplayUsage()
 		os.Exit(1)
 	}
 
 	// Determine and execute the appropriate mode
-	if err := determineExecutionMode(flags, args, config); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	exitOnError(determineExecutionMode(flags, args, config))
+}
+
+// exitOnError reports err and exits with the code it requests (1 by default); nil is a no-op
+func exitOnError(err error) {
+	if err == nil {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Error: %v\n", redact(err.Error()))
+	var exitErr *ExitCodeError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.Code)
 	}
+	os.Exit(1)
 }
@@ -75,7 +75,7 @@ func TestGenerateMarkdownFromJSON(t *testing.T) {
 			require.NoError(t, err)
 
 			// Test GenerateMarkdownFromJSON
-			err = GenerateMarkdownFromJSON(inputFile, outputFile)
+			err = GenerateMarkdownFromJSON(inputFile, outputFile, "", ReportOptions{})
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -95,7 +95,7 @@ func TestGenerateMarkdownFromJSON(t *testing.T) {
 
 	// Test with non-existent file
 	t.Run("Non-existent input file", func(t *testing.T) {
-		err := GenerateMarkdownFromJSON("/non/existent/file.json", "/tmp/output.md")
+		err := GenerateMarkdownFromJSON("/non/existent/file.json", "/tmp/output.md", "", ReportOptions{})
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "error reading JSON file")
 	})
@@ -106,7 +106,7 @@ func TestGenerateMarkdownFromJSON(t *testing.T) {
 		err := os.WriteFile(inputFile, []byte(`{"tasks": []}`), 0644)
 		require.NoError(t, err)
 
-		err = GenerateMarkdownFromJSON(inputFile, "/root/invalid/path/output.md")
+		err = GenerateMarkdownFromJSON(inputFile, "/root/invalid/path/output.md", "", ReportOptions{})
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "error writing markdown file")
 	})
@@ -205,10 +205,12 @@ func TestGenerateMarkdown(t *testing.T) {
 						Reporter:    "",
 						Priority:    "",
 						Transitions: []Transition{},
+						Error:       &TaskError{Code: ErrorCodeNotFound, HTTPStatus: 404},
 					},
 				},
 			},
 			checks: []string{
+				"- **Error:** not_found (HTTP 404)",
 				"| ERR-789 | Error | Error |  | Unassigned |", // No link for error tasks
 				"### 1. ERR-789", // No link in header for error tasks
 				"**Created:** N/A",
@@ -299,11 +301,125 @@ func TestGenerateMarkdown(t *testing.T) {
 				"[MULTI-123](https://test.atlassian.net/browse/MULTI-123)",
 			},
 		},
+		{
+			name: "Task with markdown description",
+			response: TransitionCheckResponse{
+				Tasks: []JiraTransitionResult{
+					{
+						Key:                 "FMT-1",
+						Status:              "Open",
+						Description:         "Steps\n\n- Run it",
+						DescriptionMarkdown: "### Steps\n\n- **Run** it",
+						Type:                "Task",
+						Project:             "FMT",
+						Transitions:         []Transition{},
+					},
+				},
+			},
+			checks: []string{
+				"> ### Steps\n> \n> - **Run** it",
+			},
+		},
+		{
+			name: "Task with field changes",
+			response: TransitionCheckResponse{
+				Tasks: []JiraTransitionResult{
+					{
+						Key:         "FLD-1",
+						Status:      "Done",
+						Type:        "Task",
+						Project:     "FLD",
+						Transitions: []Transition{},
+						FieldChanges: []FieldChange{
+							{
+								Field:       "priority",
+								FromValue:   "Low",
+								ToValue:     "High",
+								Author:      "John Doe",
+								AuthorEmail: "john@example.com",
+								ChangeTime:  "2025-01-03T09:00:00.000+0300",
+							},
+						},
+					},
+				},
+			},
+			checks: []string{
+				"**Field Change History:**",
+				"| priority | Low | High | John Doe | 2025-01-03 09:00:00 |",
+			},
+		},
+		{
+			name: "Task with metrics and release aggregates",
+			response: TransitionCheckResponse{
+				Tasks: []JiraTransitionResult{
+					{
+						Key:         "MET-1",
+						Status:      "Done",
+						Type:        "Task",
+						Project:     "MET",
+						Transitions: []Transition{},
+						Metrics: &TaskMetrics{
+							TimeInStatus: []StatusDuration{
+								{Status: "To Do", Hours: 2},
+								{Status: "In Progress", Hours: 26.5},
+							},
+							LeadTimeHours:  floatPtr(28.5),
+							CycleTimeHours: floatPtr(26.5),
+						},
+					},
+				},
+				Aggregates: &ReleaseMetrics{
+					LeadTime: DurationStats{Count: 1, MeanHours: 28.5, MedianHours: 28.5, P90Hours: 28.5},
+				},
+			},
+			checks: []string{
+				"- **Lead Time:** 1d 4h 30m",
+				"- **Cycle Time:** 1d 2h 30m",
+				"| In Progress | 1d 2h 30m |",
+				"## Flow Metrics",
+				"| Lead Time | 1 | 1d 4h 30m | 1d 4h 30m | 1d 4h 30m |",
+				"| Cycle Time | 0 | N/A | N/A | N/A |",
+			},
+		},
+		{
+			name: "Incrementally updated evidence",
+			response: TransitionCheckResponse{
+				Tasks: []JiraTransitionResult{
+					{Key: "UPD-1", Status: "Done", Transitions: []Transition{}},
+					{Key: "UPD-3", Status: "To Do", Transitions: []Transition{}},
+				},
+				Update: &EvidenceUpdate{
+					Added:     []string{"UPD-3"},
+					Removed:   []string{"UPD-2"},
+					Changed:   []string{},
+					Unchanged: []string{"UPD-1"},
+				},
+			},
+			checks: []string{
+				"## Changes Since Previous Evidence",
+				"- **Added:** UPD-3",
+				"- **Removed:** UPD-2",
+				"- **Changed:** none",
+				"- **Unchanged:** 1",
+			},
+		},
+		{
+			name: "Merged evidence",
+			response: TransitionCheckResponse{
+				Tasks: []JiraTransitionResult{
+					{Key: "MRG-1", Status: "Done", Transitions: []Transition{}, Sources: []string{"btcwallet"}},
+					{Key: "MRG-2", Status: "To Do", Transitions: []Transition{}, Sources: []string{"btcwallet", "btcwallet-ui"}},
+				},
+			},
+			checks: []string{
+				"- **Sources:** btcwallet, btcwallet-ui",
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			markdown := generateMarkdown(tt.response)
+			markdown := renderMarkdown(t, tt.response)
 
 			// Check that all expected strings are present
 			for _, check := range tt.checks {
@@ -356,6 +472,16 @@ func TestFormatDate(t *testing.T) {
 	}
 }
 
+// renderMarkdown renders a response with the built-in report layout, as GenerateMarkdownFromJSON does
+func renderMarkdown(t *testing.T, response TransitionCheckResponse) string {
+	t.Helper()
+	tmpl, err := loadReportTemplate("", ReportOptions{})
+	require.NoError(t, err)
+	markdown, err := renderReport(tmpl, newReportData(response, "", time.Now()))
+	require.NoError(t, err)
+	return markdown
+}
+
 // Helper function to create string pointer
 func strPtr(s string) *string {
 	return &s
@@ -367,7 +493,7 @@ func TestGenerateMarkdownIncludesCurrentTime(t *testing.T) {
 		Tasks: []JiraTransitionResult{},
 	}
 
-	markdown := generateMarkdown(response)
+	markdown := renderMarkdown(t, response)
 
 	// Check that it includes a date in the expected format
 	now := time.Now()
@@ -404,7 +530,7 @@ func TestMarkdownFormattingEdgeCases(t *testing.T) {
 		},
 	}
 
-	markdown := generateMarkdown(response)
+	markdown := renderMarkdown(t, response)
 
 	// Verify the special characters are preserved in the output
 	assert.Contains(t, markdown, "Status|With|Pipes")
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MergeInput is one evidence file to merge and the build or component it belongs to
+type MergeInput struct {
+	Source   string
+	Response TransitionCheckResponse
+}
+
+// parseMergeArg splits a "component=path" merge argument; a bare path names its own component
+func parseMergeArg(arg string) (source, path string) {
+	if name, file, ok := strings.Cut(arg, "="); ok && name != "" && file != "" {
+		return name, file
+	}
+	return arg, arg
+}
+
+// mergeEvidence combines the evidence of several builds into one predicate.
+// Tickets are de-duplicated by key (case-insensitive), keeping the copy with the freshest updated timestamp;
+// copies that could not be fetched only win when no build fetched the ticket. Every ticket records the sources
+// that referenced it, and tickets keep the order in which they first appear.
+func mergeEvidence(inputs []MergeInput) (TransitionCheckResponse, error) {
+	merged := TransitionCheckResponse{
+		SchemaVersion: CurrentSchemaVersion,
+		Tasks:         []JiraTransitionResult{},
+	}
+
+	index := make(map[string]int)
+	for i, input := range inputs {
+		if i == 0 {
+			merged.AsOf = input.Response.AsOf
+		} else if input.Response.AsOf != merged.AsOf {
+			return merged, &ValidationError{Field: "as-of", Value: input.Response.AsOf,
+				Err: fmt.Errorf("evidence of %s describes another instant than %s (%s)", input.Source, inputs[0].Source, merged.AsOf)}
+		}
+
+		for _, task := range input.Response.Tasks {
+			// Evidence that was merged before already knows which components referenced the task
+			sources := task.Sources
+			if len(sources) == 0 {
+				sources = []string{input.Source}
+			}
+
+			key := strings.ToUpper(task.Key)
+			pos, seen := index[key]
+			if !seen {
+				task.Sources = appendSources(nil, sources)
+				index[key] = len(merged.Tasks)
+				merged.Tasks = append(merged.Tasks, task)
+				continue
+			}
+
+			kept := &merged.Tasks[pos]
+			allSources := appendSources(kept.Sources, sources)
+			if fresherTask(task, *kept) {
+				*kept = task
+			}
+			kept.Sources = allSources
+		}
+	}
+
+	merged.Aggregates = computeReleaseMetrics(merged.Tasks)
+	return merged, nil
+}
+
+// fresherTask reports whether candidate is a better copy of a ticket than current
+func fresherTask(candidate, current JiraTransitionResult) bool {
+	if (candidate.Status == ErrorStatus) != (current.Status == ErrorStatus) {
+		return current.Status == ErrorStatus
+	}
+	candidateUpdated, err := parseJiraTime(candidate.Updated)
+	if err != nil {
+		return false
+	}
+	currentUpdated, err := parseJiraTime(current.Updated)
+	if err != nil {
+		return true
+	}
+	return candidateUpdated.After(currentUpdated)
+}
+
+// appendSources appends the sources that are not yet listed
+func appendSources(sources []string, more []string) []string {
+	result := append([]string(nil), sources...)
+	for _, source := range more {
+		if !containsString(result, source) {
+			result = append(result, source)
+		}
+	}
+	return result
+}
+
+// runMergeCommand merges the evidence files given as arguments into one application-level predicate
+func runMergeCommand(flags *FlagConfig, args []string, config *AppConfig) error {
+	if len(args) < 2 {
+		return fmt.Errorf("expected at least two evidence files to merge, got %d", len(args))
+	}
+
+	fmt.Println("=== JIRA Evidence Merge ===")
+	inputs := make([]MergeInput, 0, len(args))
+	ticketCount := 0
+	for _, arg := range args {
+		source, path := parseMergeArg(arg)
+		response, _, err := loadTransitionResponse(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		fmt.Printf("%s: %d tickets from %s\n", source, len(response.Tasks), path)
+		inputs = append(inputs, MergeInput{Source: source, Response: response})
+		ticketCount += len(response.Tasks)
+	}
+
+	merged, err := mergeEvidence(inputs)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Merged %d tickets into %d unique tickets\n", ticketCount, len(merged.Tasks))
+	fmt.Println("")
+
+	return saveJiraResults(merged, config)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMergeArg(t *testing.T) {
+	tests := []struct {
+		arg            string
+		expectedSource string
+		expectedPath   string
+	}{
+		{arg: "btcwallet=build/btcwallet.json", expectedSource: "btcwallet", expectedPath: "build/btcwallet.json"},
+		{arg: "build/ai-translate.json", expectedSource: "build/ai-translate.json", expectedPath: "build/ai-translate.json"},
+		{arg: "=evidence.json", expectedSource: "=evidence.json", expectedPath: "=evidence.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			source, path := parseMergeArg(tt.arg)
+			assert.Equal(t, tt.expectedSource, source)
+			assert.Equal(t, tt.expectedPath, path)
+		})
+	}
+}
+
+func TestMergeEvidence(t *testing.T) {
+	lead := 10.0
+	wallet := TransitionCheckResponse{Tasks: []JiraTransitionResult{
+		{Key: "EV-1", Status: "In Progress", Updated: "2024-01-02T10:00:00.000+0000"},
+		{Key: "EV-2", Status: "Done", Updated: "2024-01-03T10:00:00.000+0000", Metrics: &TaskMetrics{LeadTimeHours: &lead}},
+	}}
+	translate := TransitionCheckResponse{Tasks: []JiraTransitionResult{
+		{Key: "EV-3", Status: ErrorStatus, Error: &TaskError{Code: ErrorCodeNetworkError}},
+		{Key: "ev-1", Status: "Done", Updated: "2024-01-05T10:00:00.000+0000"},
+	}}
+	ui := TransitionCheckResponse{Tasks: []JiraTransitionResult{
+		{Key: "EV-3", Status: "Done", Updated: "2024-01-01T10:00:00.000+0000"},
+		{Key: "EV-2", Status: "In Review", Updated: "2024-01-02T10:00:00.000+0000"},
+	}}
+
+	merged, err := mergeEvidence([]MergeInput{
+		{Source: "btcwallet", Response: wallet},
+		{Source: "ai-translate", Response: translate},
+		{Source: "btcwallet-ui", Response: ui},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, CurrentSchemaVersion, merged.SchemaVersion)
+	require.Len(t, merged.Tasks, 3)
+
+	// The freshest copy wins, whichever build it came from
+	assert.Equal(t, "ev-1", merged.Tasks[0].Key)
+	assert.Equal(t, "Done", merged.Tasks[0].Status)
+	assert.Equal(t, []string{"btcwallet", "ai-translate"}, merged.Tasks[0].Sources)
+
+	assert.Equal(t, "Done", merged.Tasks[1].Status)
+	assert.Equal(t, []string{"btcwallet", "btcwallet-ui"}, merged.Tasks[1].Sources)
+
+	// A fetched copy beats a failed one even when it is older
+	assert.Equal(t, "Done", merged.Tasks[2].Status)
+	assert.Nil(t, merged.Tasks[2].Error)
+	assert.Equal(t, []string{"ai-translate", "btcwallet-ui"}, merged.Tasks[2].Sources)
+
+	require.NotNil(t, merged.Aggregates)
+	assert.Equal(t, 1, merged.Aggregates.LeadTime.Count)
+
+	t.Run("Merged evidence can be merged again", func(t *testing.T) {
+		other := TransitionCheckResponse{Tasks: []JiraTransitionResult{{Key: "EV-1", Status: "Done", Updated: "2024-01-01T10:00:00.000+0000"}}}
+		remerged, err := mergeEvidence([]MergeInput{{Source: "app-1.0", Response: merged}, {Source: "docs", Response: other}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"btcwallet", "ai-translate", "docs"}, remerged.Tasks[0].Sources)
+	})
+
+	t.Run("Different as-of instants", func(t *testing.T) {
+		_, err := mergeEvidence([]MergeInput{
+			{Source: "btcwallet", Response: TransitionCheckResponse{AsOf: "2024-01-01T00:00:00.000+0000"}},
+			{Source: "ai-translate", Response: TransitionCheckResponse{}},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "evidence of ai-translate describes another instant than btcwallet")
+	})
+}
+
+func TestRunMergeCommand(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, tasks ...JiraTransitionResult) string {
+		data, err := json.Marshal(TransitionCheckResponse{SchemaVersion: CurrentSchemaVersion, Tasks: tasks})
+		require.NoError(t, err)
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, data, 0644))
+		return path
+	}
+	walletFile := write("btcwallet.json", JiraTransitionResult{Key: "EV-1", Status: "Done", Transitions: []Transition{}})
+	uiFile := write("ui.json", JiraTransitionResult{Key: "EV-1", Status: "Done", Transitions: []Transition{}},
+		JiraTransitionResult{Key: "EV-2", Status: "To Do", Transitions: []Transition{}})
+	outputFile := filepath.Join(dir, "app.json")
+
+	err := runMergeCommand(&FlagConfig{}, []string{"btcwallet=" + walletFile, uiFile}, &AppConfig{OutputFile: outputFile})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	var merged TransitionCheckResponse
+	require.NoError(t, json.Unmarshal(data, &merged))
+	require.Len(t, merged.Tasks, 2)
+	assert.Equal(t, []string{"btcwallet", uiFile}, merged.Tasks[0].Sources)
+	assert.Equal(t, []string{uiFile}, merged.Tasks[1].Sources)
+
+	err = runMergeCommand(&FlagConfig{}, []string{walletFile}, &AppConfig{OutputFile: outputFile})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected at least two evidence files")
+}
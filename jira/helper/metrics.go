@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Constants for default status classification used by the metrics
+const (
+	DefaultDoneStatuses       = "Done,Closed,Resolved"
+	DefaultInProgressStatuses = "In Progress"
+)
+
+// MetricsOptions controls how statuses are classified when computing metrics
+type MetricsOptions struct {
+	DoneStatuses       []string
+	InProgressStatuses []string
+	// Now is the reference time for tickets still open, usually the generation time
+	Now time.Time
+}
+
+// computeTaskMetrics computes time-in-status, lead time and cycle time for a single task.
+// Returns nil for error results or tasks whose dates cannot be parsed.
+func computeTaskMetrics(task JiraTransitionResult, opts MetricsOptions) *TaskMetrics {
+	if task.Status == ErrorStatus {
+		return nil
+	}
+
+	created, err := parseJiraTime(task.Created)
+	if err != nil {
+		return nil
+	}
+
+	transitions := sortedTransitions(task.Transitions)
+
+	// The status a ticket was created in is the source of its first transition
+	currentStatus := task.Status
+	if len(transitions) > 0 {
+		currentStatus = transitions[0].FromStatus
+	}
+
+	durations := make(map[string]time.Duration)
+	var order []string
+	addDuration := func(status string, d time.Duration) {
+		if _, seen := durations[status]; !seen {
+			order = append(order, status)
+		}
+		if d > 0 {
+			durations[status] += d
+		}
+	}
+
+	var firstInProgress, doneAt *time.Time
+	since := created
+	for _, transition := range transitions {
+		at, err := parseJiraTime(transition.TransitionTime)
+		if err != nil {
+			currentStatus = transition.ToStatus
+			continue
+		}
+		addDuration(currentStatus, at.Sub(since))
+
+		if firstInProgress == nil && statusIn(transition.ToStatus, opts.InProgressStatuses) {
+			t := at
+			firstInProgress = &t
+		}
+		if statusIn(transition.ToStatus, opts.DoneStatuses) {
+			t := at
+			doneAt = &t
+		} else {
+			doneAt = nil // Reopened tickets are no longer done
+		}
+
+		currentStatus = transition.ToStatus
+		since = at
+	}
+
+	// Time in the final status keeps accruing until the ticket is done
+	if !statusIn(currentStatus, opts.DoneStatuses) {
+		addDuration(currentStatus, opts.Now.Sub(since))
+	} else {
+		addDuration(currentStatus, 0)
+	}
+
+	metrics := &TaskMetrics{
+		TimeInStatus: make([]StatusDuration, 0, len(order)),
+	}
+	for _, status := range order {
+		metrics.TimeInStatus = append(metrics.TimeInStatus, StatusDuration{
+			Status: status,
+			Hours:  roundHours(durations[status]),
+		})
+	}
+
+	if doneAt != nil {
+		lead := roundHours(doneAt.Sub(created))
+		metrics.LeadTimeHours = &lead
+		if firstInProgress != nil && !firstInProgress.After(*doneAt) {
+			cycle := roundHours(doneAt.Sub(*firstInProgress))
+			metrics.CycleTimeHours = &cycle
+		}
+	}
+
+	return metrics
+}
+
+// computeReleaseMetrics aggregates lead and cycle times across all tasks that have them
+func computeReleaseMetrics(tasks []JiraTransitionResult) *ReleaseMetrics {
+	var leadTimes, cycleTimes []float64
+	for _, task := range tasks {
+		if task.Metrics == nil {
+			continue
+		}
+		if task.Metrics.LeadTimeHours != nil {
+			leadTimes = append(leadTimes, *task.Metrics.LeadTimeHours)
+		}
+		if task.Metrics.CycleTimeHours != nil {
+			cycleTimes = append(cycleTimes, *task.Metrics.CycleTimeHours)
+		}
+	}
+
+	return &ReleaseMetrics{
+		LeadTime:  summarizeDurations(leadTimes),
+		CycleTime: summarizeDurations(cycleTimes),
+	}
+}
+
+// addMetrics fills in per-task metrics and release aggregates on the response
+func addMetrics(response *TransitionCheckResponse, opts MetricsOptions) {
+	for i := range response.Tasks {
+		response.Tasks[i].Metrics = computeTaskMetrics(response.Tasks[i], opts)
+	}
+	response.Aggregates = computeReleaseMetrics(response.Tasks)
+}
+
+// summarizeDurations computes count, mean, median and p90 of durations given in hours
+func summarizeDurations(hours []float64) DurationStats {
+	stats := DurationStats{Count: len(hours)}
+	if len(hours) == 0 {
+		return stats
+	}
+
+	sorted := append([]float64(nil), hours...)
+	sort.Float64s(sorted)
+
+	sum := 0.0
+	for _, h := range sorted {
+		sum += h
+	}
+
+	stats.MeanHours = roundTo(sum/float64(len(sorted)), 2)
+	stats.MedianHours = roundTo(median(sorted), 2)
+	stats.P90Hours = roundTo(percentile(sorted, 90), 2)
+	return stats
+}
+
+// median returns the median of an ascending slice
+func median(sorted []float64) float64 {
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// percentile returns the nearest-rank percentile of an ascending slice
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// sortedTransitions returns the transitions ordered by transition time (stable for equal times)
+func sortedTransitions(transitions []Transition) []Transition {
+	times := make([]string, len(transitions))
+	for i, transition := range transitions {
+		times[i] = transition.TransitionTime
+	}
+	var sorted []Transition
+	for _, i := range timeOrder(times) {
+		sorted = append(sorted, transitions[i])
+	}
+	return sorted
+}
+
+// timeOrder returns the indexes of JIRA timestamps in chronological order (stable for equal times).
+// Each timestamp is parsed once; unparseable ones follow the others in their original order.
+func timeOrder(values []string) []int {
+	type entry struct {
+		index int
+		at    time.Time
+		ok    bool
+	}
+	entries := make([]entry, len(values))
+	for i, value := range values {
+		at, err := parseJiraTime(value)
+		entries[i] = entry{index: i, at: at, ok: err == nil}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].ok != entries[j].ok {
+			return entries[i].ok
+		}
+		return entries[i].ok && entries[i].at.Before(entries[j].at)
+	})
+
+	order := make([]int, len(entries))
+	for i, e := range entries {
+		order[i] = e.index
+	}
+	return order
+}
+
+// parseJiraTime parses a JIRA timestamp, also accepting RFC 3339
+func parseJiraTime(value string) (time.Time, error) {
+	if t, err := time.Parse(JiraTimeFormat, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// statusIn reports whether status is in the list (case-insensitive)
+func statusIn(status string, statuses []string) bool {
+	for _, s := range statuses {
+		if strings.EqualFold(s, status) {
+			return true
+		}
+	}
+	return false
+}
+
+func roundHours(d time.Duration) float64 {
+	return roundTo(d.Hours(), 2)
+}
+
+func roundTo(value float64, places int) float64 {
+	factor := math.Pow(10, float64(places))
+	return math.Round(value*factor) / factor
+}
+
+// formatHours formats a duration in hours as a compact "2d 3h 15m" string
+func formatHours(hours float64) string {
+	minutes := int(math.Round(hours * 60))
+	if minutes <= 0 {
+		return "0m"
+	}
+
+	days := minutes / (24 * 60)
+	minutes -= days * 24 * 60
+	h := minutes / 60
+	minutes -= h * 60
+
+	var parts []string
+	if days > 0 {
+		parts = append(parts, fmt.Sprintf("%dd", days))
+	}
+	if h > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", h))
+	}
+	if minutes > 0 {
+		parts = append(parts, fmt.Sprintf("%dm", minutes))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func defaultMetricsOptions(now time.Time) MetricsOptions {
+	return MetricsOptions{
+		DoneStatuses:       parseFieldList(DefaultDoneStatuses),
+		InProgressStatuses: parseFieldList(DefaultInProgressStatuses),
+		Now:                now,
+	}
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func TestComputeTaskMetrics(t *testing.T) {
+	now := time.Date(2025, 1, 10, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		task             JiraTransitionResult
+		expectNil        bool
+		expectedStatuses []StatusDuration
+		expectedLead     *float64
+		expectedCycle    *float64
+	}{
+		{
+			name: "Done ticket with full history",
+			task: JiraTransitionResult{
+				Key:     "EV-1",
+				Status:  "Done",
+				Created: "2025-01-01T10:00:00.000+0000",
+				Transitions: []Transition{
+					// Deliberately out of order to verify sorting
+					{FromStatus: "In Progress", ToStatus: "Done", TransitionTime: "2025-01-03T10:00:00.000+0000"},
+					{FromStatus: "To Do", ToStatus: "In Progress", TransitionTime: "2025-01-01T16:00:00.000+0000"},
+				},
+			},
+			expectedStatuses: []StatusDuration{
+				{Status: "To Do", Hours: 6},
+				{Status: "In Progress", Hours: 42},
+				{Status: "Done", Hours: 0},
+			},
+			expectedLead:  floatPtr(48),
+			expectedCycle: floatPtr(42),
+		},
+		{
+			name: "Open ticket accrues time until now",
+			task: JiraTransitionResult{
+				Key:     "EV-2",
+				Status:  "In Progress",
+				Created: "2025-01-09T10:00:00.000+0000",
+				Transitions: []Transition{
+					{FromStatus: "To Do", ToStatus: "In Progress", TransitionTime: "2025-01-09T22:00:00.000+0000"},
+				},
+			},
+			expectedStatuses: []StatusDuration{
+				{Status: "To Do", Hours: 12},
+				{Status: "In Progress", Hours: 12},
+			},
+		},
+		{
+			name: "Reopened ticket is not done",
+			task: JiraTransitionResult{
+				Key:     "EV-3",
+				Status:  "Reopened",
+				Created: "2025-01-08T10:00:00.000+0000",
+				Transitions: []Transition{
+					{FromStatus: "To Do", ToStatus: "Done", TransitionTime: "2025-01-09T10:00:00.000+0000"},
+					{FromStatus: "Done", ToStatus: "Reopened", TransitionTime: "2025-01-10T08:00:00.000+0000"},
+				},
+			},
+			expectedStatuses: []StatusDuration{
+				{Status: "To Do", Hours: 24},
+				{Status: "Done", Hours: 22},
+				{Status: "Reopened", Hours: 2},
+			},
+		},
+		{
+			name: "Done without passing through In Progress has no cycle time",
+			task: JiraTransitionResult{
+				Key:     "EV-4",
+				Status:  "Closed",
+				Created: "2025-01-01T10:00:00.000+0000",
+				Transitions: []Transition{
+					{FromStatus: "Open", ToStatus: "Closed", TransitionTime: "2025-01-01T12:30:00.000+0000"},
+				},
+			},
+			expectedStatuses: []StatusDuration{
+				{Status: "Open", Hours: 2.5},
+				{Status: "Closed", Hours: 0},
+			},
+			expectedLead: floatPtr(2.5),
+		},
+		{
+			name: "Ticket without transitions",
+			task: JiraTransitionResult{
+				Key:     "EV-5",
+				Status:  "To Do",
+				Created: "2025-01-10T09:00:00.000+0000",
+			},
+			expectedStatuses: []StatusDuration{
+				{Status: "To Do", Hours: 1},
+			},
+		},
+		{
+			name:      "Error result",
+			task:      JiraTransitionResult{Key: "EV-6", Status: ErrorStatus},
+			expectNil: true,
+		},
+		{
+			name:      "Unparseable created date",
+			task:      JiraTransitionResult{Key: "EV-7", Status: "Done", Created: "not-a-date"},
+			expectNil: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := computeTaskMetrics(tt.task, defaultMetricsOptions(now))
+
+			if tt.expectNil {
+				assert.Nil(t, metrics)
+				return
+			}
+
+			require.NotNil(t, metrics)
+			assert.Equal(t, tt.expectedStatuses, metrics.TimeInStatus)
+			assert.Equal(t, tt.expectedLead, metrics.LeadTimeHours)
+			assert.Equal(t, tt.expectedCycle, metrics.CycleTimeHours)
+		})
+	}
+}
+
+func TestSummarizeDurations(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []float64
+		expected DurationStats
+	}{
+		{
+			name:     "No values",
+			input:    nil,
+			expected: DurationStats{},
+		},
+		{
+			name:     "Single value",
+			input:    []float64{5},
+			expected: DurationStats{Count: 1, MeanHours: 5, MedianHours: 5, P90Hours: 5},
+		},
+		{
+			name:     "Even count uses middle average",
+			input:    []float64{4, 1, 3, 2},
+			expected: DurationStats{Count: 4, MeanHours: 2.5, MedianHours: 2.5, P90Hours: 4},
+		},
+		{
+			name:     "Ten values nearest-rank p90",
+			input:    []float64{10, 9, 8, 7, 6, 5, 4, 3, 2, 1},
+			expected: DurationStats{Count: 10, MeanHours: 5.5, MedianHours: 5.5, P90Hours: 9},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, summarizeDurations(tt.input))
+		})
+	}
+}
+
+func TestAddMetrics(t *testing.T) {
+	now := time.Date(2025, 1, 10, 10, 0, 0, 0, time.UTC)
+	response := TransitionCheckResponse{
+		Tasks: []JiraTransitionResult{
+			{
+				Key:     "EV-1",
+				Status:  "Done",
+				Created: "2025-01-01T10:00:00.000+0000",
+				Transitions: []Transition{
+					{FromStatus: "To Do", ToStatus: "In Progress", TransitionTime: "2025-01-02T10:00:00.000+0000"},
+					{FromStatus: "In Progress", ToStatus: "Done", TransitionTime: "2025-01-03T10:00:00.000+0000"},
+				},
+			},
+			{Key: "EV-2", Status: ErrorStatus},
+		},
+	}
+
+	addMetrics(&response, defaultMetricsOptions(now))
+
+	require.NotNil(t, response.Tasks[0].Metrics)
+	assert.Nil(t, response.Tasks[1].Metrics)
+	require.NotNil(t, response.Aggregates)
+	assert.Equal(t, DurationStats{Count: 1, MeanHours: 48, MedianHours: 48, P90Hours: 48}, response.Aggregates.LeadTime)
+	assert.Equal(t, DurationStats{Count: 1, MeanHours: 24, MedianHours: 24, P90Hours: 24}, response.Aggregates.CycleTime)
+}
+
+func TestFormatHours(t *testing.T) {
+	tests := []struct {
+		input    float64
+		expected string
+	}{
+		{input: 0, expected: "0m"},
+		{input: 0.5, expected: "30m"},
+		{input: 2, expected: "2h"},
+		{input: 26.25, expected: "1d 2h 15m"},
+		{input: 48, expected: "2d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatHours(tt.input))
+		})
+	}
+}
+
+func TestSortedTransitions(t *testing.T) {
+	transitions := []Transition{
+		{ToStatus: "Done", TransitionTime: "2025-01-03T10:00:00.000+0000"},
+		{ToStatus: "Imported", TransitionTime: "not a timestamp"},
+		{ToStatus: "In Progress", TransitionTime: "2025-01-01T10:00:00.000+0000"},
+		{ToStatus: "Migrated", TransitionTime: ""},
+		{ToStatus: "In Review", TransitionTime: "2025-01-02T10:00:00.000+0000"},
+		{ToStatus: "Reviewed", TransitionTime: "2025-01-02T10:00:00.000+0000"},
+	}
+
+	var statuses []string
+	for _, transition := range sortedTransitions(transitions) {
+		statuses = append(statuses, transition.ToStatus)
+	}
+	// Unparseable times follow the others in their original order
+	assert.Equal(t, []string{"In Progress", "In Review", "Reviewed", "Done", "Imported", "Migrated"}, statuses)
+	assert.Equal(t, "Done", transitions[0].ToStatus, "the input is left untouched")
+	assert.Nil(t, sortedTransitions(nil))
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Constants for schema migration
+const (
+	MigrateCommand = "migrate"
+	// LegacySchemaVersion is assumed for files written before schema_version existed
+	LegacySchemaVersion = "1.0.0"
+)
+
+// MigrationReport describes how a predicate file was upgraded
+type MigrationReport struct {
+	FromVersion string
+	ToVersion   string
+	// Changes lists every value that was defaulted, converted or dropped, as "path: description"
+	Changes []string
+}
+
+// Migrated reports whether the document needed any change
+func (r *MigrationReport) Migrated() bool {
+	return r.FromVersion != r.ToVersion || len(r.Changes) > 0
+}
+
+func (r *MigrationReport) add(path, format string, args ...interface{}) {
+	r.Changes = append(r.Changes, path+": "+fmt.Sprintf(format, args...))
+}
+
+// schemaMigration upgrades a decoded document from one schema version to the next
+type schemaMigration struct {
+	From  string
+	To    string
+	Apply func(doc map[string]interface{}, report *MigrationReport)
+}
+
+// schemaMigrations is the ordered chain of upgrades; append a step whenever CurrentSchemaVersion is bumped
+var schemaMigrations = []schemaMigration{
+	{From: LegacySchemaVersion, To: "1.1.0", Apply: migrateLegacyTo110},
+	{From: "1.1.0", To: "1.2.0", Apply: migrate110To120},
+	{From: "1.2.0", To: "1.3.0", Apply: migrate120To130},
+	{From: "1.3.0", To: "1.4.0", Apply: migrate130To140},
+	{From: "1.4.0", To: "1.5.0", Apply: migrate140To150},
+}
+
+// migrateLegacyTo110 upgrades unversioned predicates: error tasks gain a structured error object
+func migrateLegacyTo110(doc map[string]interface{}, report *MigrationReport) {
+	tasks, _ := doc["tasks"].([]interface{})
+	for i, item := range tasks {
+		task, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if status, _ := task["status"].(string); status != ErrorStatus {
+			continue
+		}
+		if _, ok := task["error"]; ok {
+			continue
+		}
+		description, _ := task["description"].(string)
+		task["error"] = map[string]interface{}{
+			"code":      ErrorCodeUnknown,
+			"retryable": false,
+			"message":   strings.TrimPrefix(description, "Error: "),
+		}
+		report.add(fmt.Sprintf("$.tasks[%d].error", i), "derived from description with code '%s'", ErrorCodeUnknown)
+	}
+}
+
+// migrate110To120 needs no changes: 1.2.0 only adds the optional update annotation
+func migrate110To120(doc map[string]interface{}, report *MigrationReport) {}
+
+// migrate120To130 needs no changes: 1.3.0 only adds the optional sources of merged tasks
+func migrate120To130(doc map[string]interface{}, report *MigrationReport) {}
+
+// migrate130To140 needs no changes: 1.4.0 only adds the optional summary, labels and components of tasks
+func migrate130To140(doc map[string]interface{}, report *MigrationReport) {}
+
+// migrate140To150 needs no changes: 1.5.0 only adds the optional fix versions of tasks
+func migrate140To150(doc map[string]interface{}, report *MigrationReport) {}
+
+// migrateResponse upgrades predicate JSON of any known schema version to the current version
+func migrateResponse(data []byte) (TransitionCheckResponse, *MigrationReport, error) {
+	var response TransitionCheckResponse
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return response, nil, fmt.Errorf("error parsing JSON: %v", err)
+	}
+
+	version, _ := doc["schema_version"].(string)
+	if version == "" {
+		version = LegacySchemaVersion
+	}
+	report := &MigrationReport{FromVersion: version, ToVersion: CurrentSchemaVersion}
+
+	for _, step := range schemaMigrations {
+		if step.From == version {
+			step.Apply(doc, report)
+			version = step.To
+		}
+	}
+	if version != CurrentSchemaVersion {
+		return response, nil, fmt.Errorf("unsupported schema version %s (current: %s)", report.FromVersion, CurrentSchemaVersion)
+	}
+	doc["schema_version"] = CurrentSchemaVersion
+
+	// Fill in anything the current schema requires but older files lack, and drop what it no longer knows
+	var schema map[string]interface{}
+	if err := json.Unmarshal(embeddedSchema, &schema); err != nil {
+		return response, nil, fmt.Errorf("invalid embedded schema: %v", err)
+	}
+	normalizer := &schemaNormalizer{root: schema, report: report}
+	normalizer.normalize(doc, schema, "$")
+
+	normalized, err := json.Marshal(doc)
+	if err != nil {
+		return response, nil, fmt.Errorf("error marshaling migrated JSON: %v", err)
+	}
+	if err := validateAgainstSchema(normalized); err != nil {
+		return response, nil, fmt.Errorf("migrated data does not match predicate schema %s: %v", CurrentSchemaVersion, err)
+	}
+	if err := json.Unmarshal(normalized, &response); err != nil {
+		return response, nil, fmt.Errorf("error parsing migrated JSON: %v", err)
+	}
+
+	return response, report, nil
+}
+
+// loadTransitionResponse reads a predicate file of any known schema version, upgrading it to the current one
+func loadTransitionResponse(inputFile string) (TransitionCheckResponse, *MigrationReport, error) {
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		return TransitionCheckResponse{}, nil, fmt.Errorf("error reading JSON file: %v", err)
+	}
+	return migrateResponse(data)
+}
+
+// schemaNormalizer defaults missing required properties and drops properties the schema does not define,
+// recording each change in the report
+type schemaNormalizer struct {
+	root   map[string]interface{}
+	report *MigrationReport
+}
+
+func (n *schemaNormalizer) normalize(value interface{}, schema map[string]interface{}, path string) {
+	schema = n.resolve(schema)
+
+	switch val := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		if properties == nil {
+			if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+				for key, child := range val {
+					n.normalize(child, additional, path+"."+key)
+				}
+			}
+			return
+		}
+
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, item := range required {
+				name := item.(string)
+				if _, present := val[name]; present {
+					continue
+				}
+				propSchema, _ := properties[name].(map[string]interface{})
+				val[name] = defaultForSchema(n.resolve(propSchema))
+				n.report.add(path+"."+name, "missing, defaulted to %s", describeDefault(val[name]))
+			}
+		}
+
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			propSchema, ok := properties[key].(map[string]interface{})
+			if !ok {
+				delete(val, key)
+				n.report.add(path+"."+key, "not part of schema %s, dropped", CurrentSchemaVersion)
+				continue
+			}
+			n.normalize(val[key], propSchema, path+"."+key)
+		}
+
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range val {
+				n.normalize(item, items, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	}
+}
+
+func (n *schemaNormalizer) resolve(schema map[string]interface{}) map[string]interface{} {
+	if ref, ok := schema["$ref"].(string); ok {
+		defs, _ := n.root["$defs"].(map[string]interface{})
+		if resolved, ok := defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{}); ok {
+			return resolved
+		}
+	}
+	return schema
+}
+
+// defaultForSchema returns the zero value for a schema type; nullable types default to null,
+// except arrays, which default to empty so consumers can iterate them
+func defaultForSchema(schema map[string]interface{}) interface{} {
+	if _, ok := schema["anyOf"]; ok {
+		return nil
+	}
+
+	types := []string{}
+	switch typ := schema["type"].(type) {
+	case string:
+		types = append(types, typ)
+	case []interface{}:
+		for _, t := range typ {
+			types = append(types, t.(string))
+		}
+	}
+
+	for _, typ := range types {
+		switch typ {
+		case "array":
+			return []interface{}{}
+		case "null":
+			return nil
+		}
+	}
+	if len(types) == 0 {
+		return map[string]interface{}{}
+	}
+	switch types[0] {
+	case "string":
+		if constant, ok := schema["const"].(string); ok {
+			return constant
+		}
+		return ""
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+func describeDefault(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// runMigrateMode upgrades a historical predicate file to the current schema version
+func runMigrateMode(args []string, config *AppConfig) error {
+	if len(args) < 1 {
+		fmt.Println("Usage: ./main migrate <input_json> [-o output_json]")
+		return fmt.Errorf("missing input file")
+	}
+	inputFile := args[0]
+
+	fmt.Println("=== Schema Migration Mode ===")
+	fmt.Printf("Input JSON file: %s\n", inputFile)
+	fmt.Printf("Output JSON file: %s\n", config.OutputFile)
+	fmt.Println("")
+
+	response, report, err := loadTransitionResponse(inputFile)
+	if err != nil {
+		return err
+	}
+
+	printMigrationReport(report)
+
+	if err := saveJiraResults(response, config); err != nil {
+		return err
+	}
+
+	fmt.Println("")
+	fmt.Println("=== Migration completed successfully ===")
+	return nil
+}
+
+// printMigrationReport prints the version upgrade and every defaulted value
+func printMigrationReport(report *MigrationReport) {
+	if !report.Migrated() {
+		fmt.Printf("Already at schema version %s, nothing to migrate\n", report.ToVersion)
+		return
+	}
+
+	fmt.Printf("Migrated schema version %s -> %s\n", report.FromVersion, report.ToVersion)
+	if len(report.Changes) == 0 {
+		return
+	}
+	fmt.Printf("Changes (%d):\n", len(report.Changes))
+	for _, change := range report.Changes {
+		fmt.Printf("  - %s\n", change)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// legacyEvidence is a predicate as written before schema_version existed
+const legacyEvidence = `{
+	"tasks": [
+		{
+			"key": "EV-1",
+			"link": "https://example.atlassian.net/browse/EV-1",
+			"status": "Done",
+			"description": "Legacy task",
+			"type": "Task",
+			"project": "EV",
+			"created": "2024-01-01T10:00:00.000+0000",
+			"updated": "2024-01-02T10:00:00.000+0000",
+			"assignee": "Alice",
+			"reporter": "Bob",
+			"priority": "High",
+			"transitions": null
+		},
+		{
+			"key": "EV-2",
+			"status": "Error",
+			"description": "Error: Could not retrieve issue",
+			"type": "Error",
+			"project": "",
+			"created": "",
+			"updated": "",
+			"assignee": null,
+			"reporter": "",
+			"priority": "",
+			"transitions": [],
+			"legacy_flag": true
+		},
+		{
+			"key": "EV-3",
+			"status": "Open"
+		}
+	]
+}`
+
+func TestMigrateResponse(t *testing.T) {
+	response, report, err := migrateResponse([]byte(legacyEvidence))
+	require.NoError(t, err)
+
+	assert.Equal(t, LegacySchemaVersion, report.FromVersion)
+	assert.Equal(t, CurrentSchemaVersion, report.ToVersion)
+	assert.True(t, report.Migrated())
+	assert.Equal(t, CurrentSchemaVersion, response.SchemaVersion)
+	require.Len(t, response.Tasks, 3)
+
+	// Existing data is preserved
+	assert.Equal(t, "EV-1", response.Tasks[0].Key)
+	assert.Equal(t, "Alice", *response.Tasks[0].Assignee)
+
+	// Error tasks gain a structured error derived from the description
+	require.NotNil(t, response.Tasks[1].Error)
+	assert.Equal(t, ErrorCodeUnknown, response.Tasks[1].Error.Code)
+	assert.Equal(t, "Could not retrieve issue", response.Tasks[1].Error.Message)
+
+	// Sparse tasks get the required fields defaulted
+	assert.Equal(t, "", response.Tasks[2].Reporter)
+	assert.Nil(t, response.Tasks[2].Assignee)
+	assert.NotNil(t, response.Tasks[2].Transitions)
+
+	assert.Contains(t, report.Changes, "$.tasks[1].error: derived from description with code 'unknown'")
+	assert.Contains(t, report.Changes, "$.tasks[1].legacy_flag: not part of schema "+CurrentSchemaVersion+", dropped")
+	assert.Contains(t, report.Changes, "$.tasks[2].assignee: missing, defaulted to null")
+	assert.Contains(t, report.Changes, "$.tasks[2].transitions: missing, defaulted to []")
+}
+
+func TestMigrateResponseCurrentVersion(t *testing.T) {
+	current := TransitionCheckResponse{
+		SchemaVersion: CurrentSchemaVersion,
+		Tasks: []JiraTransitionResult{
+			{Key: "EV-1", Status: "Done", Transitions: []Transition{}},
+		},
+	}
+	data, err := json.Marshal(current)
+	require.NoError(t, err)
+
+	response, report, err := migrateResponse(data)
+	require.NoError(t, err)
+	assert.False(t, report.Migrated())
+	assert.Empty(t, report.Changes)
+	assert.Equal(t, current, response)
+}
+
+func TestMigrateResponseFrom110(t *testing.T) {
+	data := []byte(`{"schema_version": "1.1.0", "tasks": [{"key": "EV-1", "status": "Done", "description": "", "type": "Task",
+		"project": "EV", "created": "", "updated": "", "assignee": null, "reporter": "", "priority": "", "transitions": []}]}`)
+
+	response, report, err := migrateResponse(data)
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0", report.FromVersion)
+	assert.Empty(t, report.Changes)
+	assert.Equal(t, CurrentSchemaVersion, response.SchemaVersion)
+	assert.Nil(t, response.Update)
+	require.Len(t, response.Tasks, 1)
+	assert.Equal(t, "EV-1", response.Tasks[0].Key)
+}
+
+func TestMigrateResponseErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          string
+		errorContains string
+	}{
+		{
+			name:          "Invalid JSON",
+			data:          `{invalid`,
+			errorContains: "error parsing JSON",
+		},
+		{
+			name:          "Unknown future version",
+			data:          `{"schema_version": "9.0.0", "tasks": []}`,
+			errorContains: "unsupported schema version 9.0.0",
+		},
+		{
+			name:          "Wrong types cannot be migrated",
+			data:          `{"tasks": [{"key": 42}]}`,
+			errorContains: "does not match predicate schema",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := migrateResponse([]byte(tt.data))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorContains)
+		})
+	}
+}
+
+func TestRunMigrateMode(t *testing.T) {
+	tempDir := t.TempDir()
+	inputFile := filepath.Join(tempDir, "legacy.json")
+	outputFile := filepath.Join(tempDir, "migrated.json")
+	require.NoError(t, os.WriteFile(inputFile, []byte(legacyEvidence), 0644))
+
+	// Missing input argument
+	err := runMigrateMode([]string{}, &AppConfig{OutputFile: outputFile})
+	assert.Error(t, err)
+
+	err = runMigrateMode([]string{inputFile}, &AppConfig{OutputFile: outputFile})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.NoError(t, validateAgainstSchema(data))
+
+	var migrated TransitionCheckResponse
+	require.NoError(t, json.Unmarshal(data, &migrated))
+	assert.Equal(t, CurrentSchemaVersion, migrated.SchemaVersion)
+	assert.Len(t, migrated.Tasks, 3)
+}
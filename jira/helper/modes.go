@@ -6,6 +6,7 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"time"
 )
 
 // runExtractOnlyMode runs the tool in extract-only mode
@@ -156,15 +157,12 @@ func runFullMode(config *AppConfig) error {
 	fmt.Println("")
 	fmt.Println("Step 2: Fetching JIRA details...")
 
-	// Create JIRA client
-	jiraClient, err := NewJiraClient()
+	// Process JIRA IDs and get results
+	response, err := fetchJiraResponse(config, config.now())
 	if err != nil {
-		return fmt.Errorf("error creating JIRA client: %v", err)
+		return err
 	}
 
-	// Process JIRA IDs and get results
-	response := jiraClient.FetchJiraDetails(config.JIRAIDs)
-
 	// Step 3: Write results to file
 	fmt.Println("")
 	fmt.Println("Step 3: Writing results...")
@@ -173,6 +171,10 @@ func runFullMode(config *AppConfig) error {
 		return err
 	}
 
+	if err := exitCodeForErrors(response, config.ErrorExitCodes); err != nil {
+		return err
+	}
+
 	fmt.Println("")
 	fmt.Println("=== Process completed successfully ===")
 	return nil
@@ -182,31 +184,46 @@ func runFullMode(config *AppConfig) error {
 func processDirectJiraIDs(config *AppConfig) error {
 	fmt.Printf("Processing JIRA IDs: %s\n", strings.Join(config.JIRAIDs, ", "))
 
-	// Create a new Jira client
-	jiraClient, err := NewJiraClient()
+	// Get response
+	response, err := fetchJiraResponse(config, config.now())
 	if err != nil {
-		return fmt.Errorf("error creating JIRA client: %v", err)
+		return err
 	}
 
-	// Get response
-	response := jiraClient.FetchJiraDetails(config.JIRAIDs)
-
 	// Save results to file using the same method as other modes
 	if err := saveJiraResults(response, config); err != nil {
 		return err
 	}
 
-	return nil
+	// Fail according to the configured exit codes once the evidence is written
+	return exitCodeForErrors(response, config.ErrorExitCodes)
+}
+
+// enrichResponse applies the --as-of reconstruction and computes flow metrics on fetched results
+func enrichResponse(response *TransitionCheckResponse, config *AppConfig, now time.Time) {
+	if !config.AsOf.IsZero() {
+		applyAsOf(response, config.AsOf)
+	}
+	addMetrics(response, config.metricsOptions(config.referenceTime(now)))
 }
 
 // saveJiraResults saves JIRA results to JSON
 func saveJiraResults(response TransitionCheckResponse, config *AppConfig) error {
+	// The writer defines the format, so always stamp the current schema version
+	response.SchemaVersion = CurrentSchemaVersion
+	response.Tasks = sortedTasks(response.Tasks, config.TaskOrder)
+
 	// Save JSON
 	jsonBytes, err := json.MarshalIndent(response, "", "  ")
 	if err != nil {
 		return fmt.Errorf("error marshaling JSON: %v", err)
 	}
 
+	// Catch format drift before the predicate reaches evidence consumers
+	if err := validateAgainstSchema(jsonBytes); err != nil {
+		return fmt.Errorf("output does not match predicate schema %s: %v", CurrentSchemaVersion, err)
+	}
+
 	if err := writeToFile(config.OutputFile, jsonBytes); err != nil {
 		return fmt.Errorf("error writing to file: %v", err)
 	}
@@ -216,11 +233,22 @@ func saveJiraResults(response TransitionCheckResponse, config *AppConfig) error
 	return nil
 }
 
-// determineExecutionMode determines which mode to run based on flags and arguments
+// determineExecutionMode determines which mode to run based on flags and arguments.
+// It backs invocations without a subcommand, which predate the explicit commands and are kept for compatibility.
 func determineExecutionMode(flags *FlagConfig, args []string, config *AppConfig) error {
 	// Handle markdown generation mode
 	if flags.GenerateMarkdown {
-		return runMarkdownMode(flags)
+		return runMarkdownMode(flags, config)
+	}
+
+	// Handle schema publication
+	if len(args) > 0 && args[0] == SchemaCommand {
+		return runSchemaMode()
+	}
+
+	// Handle schema migration of historical evidence
+	if len(args) > 0 && args[0] == MigrateCommand {
+		return runMigrateMode(args[1:], config)
 	}
 
 	// Handle legacy extract-from-git mode
@@ -270,19 +298,53 @@ func allArgsMatchPattern(args []string, regex *regexp.Regexp) bool {
 	return true
 }
 
-// runMarkdownMode runs the markdown generation mode
-func runMarkdownMode(flags *FlagConfig) error {
-	// Determine input and output files
-	inputFile := getOrDefault(flags.OutputFile, os.Getenv("OUTPUT_FILE"), DefaultOutputFile)
+// runMarkdownMode runs the report generation mode, rendering markdown or a self-contained HTML page
+func runMarkdownMode(flags *FlagConfig, config *AppConfig) error {
+	opts := ReportOptions{
+		DoneStatuses:       config.DoneStatuses,
+		InProgressStatuses: config.InProgressStatuses,
+		TaskOrder:          config.TaskOrder,
+		GeneratedAt:        config.now(),
+	}
+	inputFile := resolveInputFile(flags, config)
+
+	switch strings.ToLower(getOrDefault(flags.Format, "markdown")) {
+	case "markdown", "md":
+	case "html":
+		if flags.Template != "" {
+			return &ValidationError{Field: "template", Value: flags.Template, Err: fmt.Errorf("templates apply to markdown reports only")}
+		}
+		outputFile := getOrDefault(flags.MarkdownOutput, "transformed_jira_data.html")
+
+		fmt.Println("=== HTML Report Generation Mode ===")
+		fmt.Printf("Input JSON file: %s\n", inputFile)
+		fmt.Printf("Output HTML file: %s\n", outputFile)
+		fmt.Println("")
+
+		if err := GenerateHTMLFromJSON(inputFile, outputFile, opts); err != nil {
+			return err
+		}
+
+		fmt.Println("")
+		fmt.Println("=== HTML report generation completed successfully ===")
+		return nil
+	default:
+		return &ValidationError{Field: "format", Value: flags.Format, Err: fmt.Errorf("expected markdown or html")}
+	}
+
+	// Determine output file
 	outputFile := getOrDefault(flags.MarkdownOutput, "transformed_jira_data.md")
 
 	fmt.Println("=== Markdown Generation Mode ===")
 	fmt.Printf("Input JSON file: %s\n", inputFile)
 	fmt.Printf("Output Markdown file: %s\n", outputFile)
+	if flags.Template != "" {
+		fmt.Printf("Template: %s\n", flags.Template)
+	}
 	fmt.Println("")
 
 	// Generate markdown from JSON
-	if err := GenerateMarkdownFromJSON(inputFile, outputFile); err != nil {
+	if err := GenerateMarkdownFromJSON(inputFile, outputFile, flags.Template, opts); err != nil {
 		return err
 	}
 
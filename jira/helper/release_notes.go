@@ -0,0 +1,389 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Release notes constants
+const (
+	ReleaseNotesFormatMarkdown = "markdown"
+	ReleaseNotesFormatText     = "text"
+
+	// Grouping levels for --group-by
+	GroupByType      = "type"
+	GroupByComponent = "component"
+	GroupByProject   = "project"
+	// groupByChangeType groups by Keep a Changelog category; the changelog command uses it, --group-by does not offer it
+	groupByChangeType = "change-type"
+
+	DefaultReleaseNotesGroupBy = GroupByType
+	// DefaultExcludeLabels keeps tickets labelled for internal work out of the release notes
+	DefaultExcludeLabels = "no-release-notes"
+	// UnreleasedVersion heads release notes when neither --version nor a git tag names the release
+	UnreleasedVersion = "Unreleased"
+
+	// otherChangesSection collects issue types no release notes section claims
+	otherChangesSection = "Other Changes"
+	// generalGroup collects tickets without a component or project
+	generalGroup = "General"
+)
+
+// releaseNotesGroupLevels lists the accepted --group-by levels
+var releaseNotesGroupLevels = []string{GroupByType, GroupByComponent, GroupByProject}
+
+// releaseNotesSections maps issue types (case-insensitive) to release notes sections, in the order they are shown
+var releaseNotesSections = []struct {
+	Title string
+	Types []string
+}{
+	{Title: "Features", Types: []string{"Story", "Feature", "New Feature", "Improvement", "Epic"}},
+	{Title: "Bug Fixes", Types: []string{"Bug", "Defect"}},
+	{Title: "Tasks", Types: []string{"Task", "Sub-task", "Subtask", "Chore"}},
+}
+
+// releaseNotesOptions configures which tickets release notes list and how they are grouped
+type releaseNotesOptions struct {
+	Version       string
+	Date          time.Time
+	GroupBy       []string
+	ExcludeLabels []string
+}
+
+// releaseNotes is the renderer-independent content of release notes
+type releaseNotes struct {
+	Version string
+	Date    time.Time
+	Groups  []releaseNotesGroup
+	// Entries are the notes when no grouping is configured
+	Entries []releaseNote
+	// Excluded lists the tickets left out because of an exclusion label
+	Excluded []string
+	// Skipped lists the tickets that could not be fetched, so they have no summary or type
+	Skipped []string
+}
+
+// releaseNotesGroup is a section of release notes; with two grouping levels it holds subgroups instead of entries
+type releaseNotesGroup struct {
+	Title   string
+	Groups  []releaseNotesGroup
+	Entries []releaseNote
+}
+
+// releaseNote is one ticket in the release notes
+type releaseNote struct {
+	Key     string
+	Link    string
+	Summary string
+}
+
+// parseGroupBy parses the --group-by list; "none" yields an ungrouped list
+func parseGroupBy(value string) ([]string, error) {
+	var levels []string
+	for _, level := range parseFieldList(value) {
+		level = strings.ToLower(level)
+		if !containsString(releaseNotesGroupLevels, level) {
+			return nil, &ValidationError{Field: "group-by", Value: level,
+				Err: fmt.Errorf("expected one of %s, or none", strings.Join(releaseNotesGroupLevels, ", "))}
+		}
+		if containsString(levels, level) {
+			return nil, &ValidationError{Field: "group-by", Value: level, Err: fmt.Errorf("listed twice")}
+		}
+		levels = append(levels, level)
+	}
+	if len(levels) > 2 {
+		return nil, &ValidationError{Field: "group-by", Value: value, Err: fmt.Errorf("at most two levels are supported")}
+	}
+	return levels, nil
+}
+
+// buildReleaseNotes selects and groups the tickets of an evidence response.
+// Tickets carrying an excluded label (case-insensitive) and tickets that could not be fetched are left out;
+// a ticket with several components is listed under each of them. Entries are ordered by key.
+func buildReleaseNotes(response TransitionCheckResponse, opts releaseNotesOptions) releaseNotes {
+	notes := releaseNotes{Version: getOrDefault(opts.Version, UnreleasedVersion), Date: opts.Date}
+
+	var tasks []JiraTransitionResult
+	for _, task := range sortedTasks(response.Tasks, TaskOrderKey) {
+		switch {
+		case task.Status == ErrorStatus:
+			notes.Skipped = append(notes.Skipped, task.Key)
+		case hasAnyLabel(task, opts.ExcludeLabels):
+			notes.Excluded = append(notes.Excluded, task.Key)
+		default:
+			tasks = append(tasks, task)
+		}
+	}
+
+	notes.Groups, notes.Entries = groupReleaseNotes(tasks, opts.GroupBy)
+	return notes
+}
+
+// groupReleaseNotes groups tasks by the first level and recurses into the remaining levels
+func groupReleaseNotes(tasks []JiraTransitionResult, levels []string) ([]releaseNotesGroup, []releaseNote) {
+	if len(levels) == 0 {
+		entries := make([]releaseNote, 0, len(tasks))
+		for _, task := range tasks {
+			entries = append(entries, releaseNote{Key: task.Key, Link: task.Link, Summary: strings.TrimSpace(task.Summary)})
+		}
+		return nil, entries
+	}
+
+	byTitle := make(map[string][]JiraTransitionResult)
+	for _, task := range tasks {
+		for _, title := range releaseNotesGroupTitles(task, levels[0]) {
+			byTitle[title] = append(byTitle[title], task)
+		}
+	}
+
+	var groups []releaseNotesGroup
+	for _, title := range orderedGroupTitles(byTitle, levels[0]) {
+		group := releaseNotesGroup{Title: title}
+		group.Groups, group.Entries = groupReleaseNotes(byTitle[title], levels[1:])
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// releaseNotesGroupTitles returns the groups a task belongs to at one level
+func releaseNotesGroupTitles(task JiraTransitionResult, level string) []string {
+	switch level {
+	case GroupByType:
+		return []string{releaseNotesSection(task.Type)}
+	case groupByChangeType:
+		return []string{changelogCategory(task)}
+	case GroupByComponent:
+		var titles []string
+		for _, component := range task.Components {
+			if component = strings.TrimSpace(component); component != "" && !containsString(titles, component) {
+				titles = append(titles, component)
+			}
+		}
+		if len(titles) == 0 {
+			return []string{generalGroup}
+		}
+		return titles
+	case GroupByProject:
+		project := task.Project
+		if project == "" {
+			project, _ = splitJiraKey(task.Key)
+		}
+		return []string{getOrDefault(project, generalGroup)}
+	}
+	return nil
+}
+
+// releaseNotesSection returns the section an issue type is listed under
+func releaseNotesSection(issueType string) string {
+	for _, section := range releaseNotesSections {
+		if statusIn(issueType, section.Types) {
+			return section.Title
+		}
+	}
+	return otherChangesSection
+}
+
+// orderedGroupTitles orders type sections and changelog categories as configured, and other groups alphabetically
+// with General last
+func orderedGroupTitles(byTitle map[string][]JiraTransitionResult, level string) []string {
+	var titles []string
+	if level == GroupByType {
+		for _, section := range releaseNotesSections {
+			if _, ok := byTitle[section.Title]; ok {
+				titles = append(titles, section.Title)
+			}
+		}
+		if _, ok := byTitle[otherChangesSection]; ok {
+			titles = append(titles, otherChangesSection)
+		}
+		return titles
+	}
+	if level == groupByChangeType {
+		for _, category := range changelogCategories {
+			if _, ok := byTitle[category.Title]; ok {
+				titles = append(titles, category.Title)
+			}
+		}
+		return titles
+	}
+
+	for title := range byTitle {
+		if title != generalGroup {
+			titles = append(titles, title)
+		}
+	}
+	sort.Slice(titles, func(i, j int) bool {
+		if a, b := strings.ToLower(titles[i]), strings.ToLower(titles[j]); a != b {
+			return a < b
+		}
+		return titles[i] < titles[j]
+	})
+	if _, ok := byTitle[generalGroup]; ok {
+		titles = append(titles, generalGroup)
+	}
+	return titles
+}
+
+// hasAnyLabel reports whether the task carries one of the labels (case-insensitive)
+func hasAnyLabel(task JiraTransitionResult, labels []string) bool {
+	for _, label := range task.Labels {
+		if statusIn(label, labels) {
+			return true
+		}
+	}
+	return false
+}
+
+// title returns the release notes heading
+func (n releaseNotes) title() string {
+	title := n.Version
+	if n.Version != UnreleasedVersion {
+		title = "Release " + n.Version
+	}
+	if !n.Date.IsZero() {
+		title += fmt.Sprintf(" (%s)", n.Date.Format("2006-01-02"))
+	}
+	return title
+}
+
+// empty reports whether no ticket made it into the release notes
+func (n releaseNotes) empty() bool {
+	return len(n.Groups) == 0 && len(n.Entries) == 0
+}
+
+// generateReleaseNotesMarkdown renders release notes as markdown, with linked ticket keys
+func generateReleaseNotesMarkdown(notes releaseNotes) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# %s\n\n", notes.title()))
+
+	writeEntries := func(entries []releaseNote) {
+		if len(entries) == 0 {
+			return
+		}
+		for _, entry := range entries {
+			key := linkify(entry.Key, entry.Link)
+			if entry.Summary == "" {
+				sb.WriteString(fmt.Sprintf("- %s\n", key))
+			} else {
+				sb.WriteString(fmt.Sprintf("- %s (%s)\n", entry.Summary, key))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	if notes.empty() {
+		sb.WriteString("No changes.\n")
+		return sb.String()
+	}
+	writeEntries(notes.Entries)
+	for _, group := range notes.Groups {
+		sb.WriteString(fmt.Sprintf("## %s\n\n", group.Title))
+		writeEntries(group.Entries)
+		for _, subgroup := range group.Groups {
+			sb.WriteString(fmt.Sprintf("### %s\n\n", subgroup.Title))
+			writeEntries(subgroup.Entries)
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
+
+// generateReleaseNotesText renders release notes as plain text, e.g. for emails or tag annotations
+func generateReleaseNotesText(notes releaseNotes) string {
+	var sb strings.Builder
+	title := notes.title()
+	sb.WriteString(fmt.Sprintf("%s\n%s\n\n", title, strings.Repeat("=", len([]rune(title)))))
+
+	writeEntries := func(entries []releaseNote, indent string) {
+		if len(entries) == 0 {
+			return
+		}
+		for _, entry := range entries {
+			if entry.Summary == "" {
+				sb.WriteString(fmt.Sprintf("%s- %s\n", indent, entry.Key))
+			} else {
+				sb.WriteString(fmt.Sprintf("%s- %s (%s)\n", indent, entry.Summary, entry.Key))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	if notes.empty() {
+		sb.WriteString("No changes.\n")
+		return sb.String()
+	}
+	writeEntries(notes.Entries, "")
+	for _, group := range notes.Groups {
+		sb.WriteString(fmt.Sprintf("%s\n%s\n\n", group.Title, strings.Repeat("-", len([]rune(group.Title)))))
+		writeEntries(group.Entries, "")
+		for _, subgroup := range group.Groups {
+			sb.WriteString(fmt.Sprintf("%s:\n", subgroup.Title))
+			writeEntries(subgroup.Entries, "  ")
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
+
+// releaseNotesVersion returns the --version flag, else the latest git tag, else UnreleasedVersion
+func releaseNotesVersion(flagVersion string, git *GitService) string {
+	if flagVersion != "" {
+		return flagVersion
+	}
+	if tag, err := git.LatestTag(); err == nil && tag != "" {
+		return tag
+	}
+	return UnreleasedVersion
+}
+
+// runReleaseNotesCommand renders release notes from an evidence file and prints or writes them
+func runReleaseNotesCommand(flags *FlagConfig, args []string, config *AppConfig) error {
+	format := strings.ToLower(getOrDefault(flags.Format, ReleaseNotesFormatMarkdown))
+	if format == "md" {
+		format = ReleaseNotesFormatMarkdown
+	}
+	if format != ReleaseNotesFormatMarkdown && format != ReleaseNotesFormatText {
+		return &ValidationError{Field: "format", Value: flags.Format, Err: fmt.Errorf("expected markdown or text")}
+	}
+	groupBy, err := parseGroupBy(getOrDefault(flags.GroupBy, DefaultReleaseNotesGroupBy))
+	if err != nil {
+		return err
+	}
+
+	response, _, err := loadTransitionResponse(resolveInputFile(flags, config))
+	if err != nil {
+		return err
+	}
+
+	notes := buildReleaseNotes(response, releaseNotesOptions{
+		Version:       releaseNotesVersion(flags.Version, NewGitService()),
+		Date:          config.now(),
+		GroupBy:       groupBy,
+		ExcludeLabels: parseFieldList(getOrDefault(flags.ExcludeLabels, DefaultExcludeLabels)),
+	})
+
+	output := generateReleaseNotesMarkdown(notes)
+	if format == ReleaseNotesFormatText {
+		output = generateReleaseNotesText(notes)
+	}
+
+	// Progress goes to stderr when the notes themselves are printed, so they can be piped
+	progress := os.Stdout
+	if flags.ReleaseNotesOutput == "" {
+		progress = os.Stderr
+		os.Stdout.WriteString(output)
+	} else {
+		if err := writeToFile(flags.ReleaseNotesOutput, []byte(output)); err != nil {
+			return fmt.Errorf("error writing to file: %v", err)
+		}
+		fmt.Fprintf(progress, "Release notes for %s saved to: %s\n", notes.Version, flags.ReleaseNotesOutput)
+	}
+	if len(notes.Excluded) > 0 {
+		fmt.Fprintf(progress, "Excluded by label: %s\n", strings.Join(notes.Excluded, ", "))
+	}
+	if len(notes.Skipped) > 0 {
+		fmt.Fprintf(progress, "Skipped, could not be fetched: %s\n", strings.Join(notes.Skipped, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGroupBy(t *testing.T) {
+	tests := []struct {
+		value         string
+		expected      []string
+		errorContains string
+	}{
+		{value: "type", expected: []string{GroupByType}},
+		{value: "Component, type", expected: []string{GroupByComponent, GroupByType}},
+		{value: "none", expected: nil},
+		{value: "priority", errorContains: "expected one of type, component, project, or none"},
+		{value: "type,type", errorContains: "listed twice"},
+		{value: "type,component,project", errorContains: "at most two levels"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			levels, err := parseGroupBy(tt.value)
+			if tt.errorContains != "" {
+				assert.ErrorContains(t, err, tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, levels)
+		})
+	}
+}
+
+func TestBuildReleaseNotes(t *testing.T) {
+	response := TransitionCheckResponse{Tasks: []JiraTransitionResult{
+		{Key: "EV-10", Link: "https://example.atlassian.net/browse/EV-10", Summary: "Sign release artifacts", Type: "Story",
+			Components: []string{"Pipeline", "Security"}},
+		{Key: "EV-2", Summary: "Fix report encoding", Type: "bug", Components: []string{"Reports"}},
+		{Key: "EV-3", Summary: "Bump linters", Type: "Task", Labels: []string{"No-Release-Notes"}},
+		{Key: "EV-9", Summary: "", Type: "Spike"},
+		{Key: "OPS-1", Summary: "Rotate deploy keys", Type: "Task", Components: []string{"Pipeline"}},
+		{Key: "EV-404", Status: ErrorStatus, Type: ErrorType, Error: &TaskError{Code: ErrorCodeNotFound}},
+	}}
+	opts := releaseNotesOptions{Version: "1.4.0", GroupBy: []string{GroupByType}, ExcludeLabels: []string{DefaultExcludeLabels}}
+	notes := buildReleaseNotes(response, opts)
+
+	assert.Equal(t, "1.4.0", notes.Version)
+	assert.Equal(t, []string{"EV-3"}, notes.Excluded)
+	assert.Equal(t, []string{"EV-404"}, notes.Skipped)
+	assert.Empty(t, notes.Entries)
+
+	var titles []string
+	for _, group := range notes.Groups {
+		titles = append(titles, group.Title)
+	}
+	assert.Equal(t, []string{"Features", "Bug Fixes", "Tasks", "Other Changes"}, titles)
+	assert.Equal(t, []releaseNote{{Key: "EV-10", Link: "https://example.atlassian.net/browse/EV-10", Summary: "Sign release artifacts"}},
+		notes.Groups[0].Entries)
+	assert.Equal(t, "EV-2", notes.Groups[1].Entries[0].Key)
+
+	t.Run("Components with sections", func(t *testing.T) {
+		opts := releaseNotesOptions{GroupBy: []string{GroupByComponent, GroupByType}}
+		notes := buildReleaseNotes(response, opts)
+		assert.Equal(t, UnreleasedVersion, notes.Version)
+		assert.Empty(t, notes.Excluded)
+
+		var titles []string
+		for _, group := range notes.Groups {
+			titles = append(titles, group.Title)
+		}
+		// Tickets are listed under each of their components, tickets without one under General
+		assert.Equal(t, []string{"Pipeline", "Reports", "Security", "General"}, titles)
+		pipeline := notes.Groups[0]
+		require.Len(t, pipeline.Groups, 2)
+		assert.Equal(t, "Features", pipeline.Groups[0].Title)
+		assert.Equal(t, "EV-10", pipeline.Groups[0].Entries[0].Key)
+		assert.Equal(t, "Tasks", pipeline.Groups[1].Title)
+		assert.Equal(t, "OPS-1", pipeline.Groups[1].Entries[0].Key)
+	})
+
+	t.Run("Ungrouped entries are ordered by key", func(t *testing.T) {
+		notes := buildReleaseNotes(response, releaseNotesOptions{ExcludeLabels: []string{DefaultExcludeLabels}})
+		assert.Empty(t, notes.Groups)
+		var keys []string
+		for _, entry := range notes.Entries {
+			keys = append(keys, entry.Key)
+		}
+		assert.Equal(t, []string{"EV-2", "EV-9", "EV-10", "OPS-1"}, keys)
+	})
+}
+
+func TestGenerateReleaseNotesMarkdown(t *testing.T) {
+	response := TransitionCheckResponse{Tasks: []JiraTransitionResult{
+		{Key: "EV-10", Link: "https://example.atlassian.net/browse/EV-10", Summary: "Sign release artifacts", Type: "Story", Project: "EV"},
+		{Key: "EV-2", Summary: "Fix report encoding", Type: "bug", Project: "EV"},
+		{Key: "EV-3", Summary: "Bump linters", Type: "Task", Project: "EV", Labels: []string{"No-Release-Notes"}},
+		{Key: "EV-9", Summary: "", Type: "Spike", Project: "EV"},
+		{Key: "OPS-1", Summary: "Rotate deploy keys", Type: "Task", Project: "OPS"},
+	}}
+	opts := releaseNotesOptions{
+		Version:       "1.4.0",
+		Date:          time.Date(2025, 1, 3, 9, 0, 0, 0, time.UTC),
+		GroupBy:       []string{GroupByType, GroupByProject},
+		ExcludeLabels: []string{DefaultExcludeLabels},
+	}
+	assert.Equal(t, `# Release 1.4.0 (2025-01-03)
+
+## Features
+
+### EV
+
+- Sign release artifacts ([EV-10](https://example.atlassian.net/browse/EV-10))
+
+## Bug Fixes
+
+### EV
+
+- Fix report encoding (EV-2)
+
+## Tasks
+
+### OPS
+
+- Rotate deploy keys (OPS-1)
+
+## Other Changes
+
+### EV
+
+- EV-9
+`, generateReleaseNotesMarkdown(buildReleaseNotes(response, opts)))
+
+	empty := buildReleaseNotes(TransitionCheckResponse{}, releaseNotesOptions{Version: "1.4.1"})
+	assert.Equal(t, "# Release 1.4.1\n\nNo changes.\n", generateReleaseNotesMarkdown(empty))
+}
+
+func TestGenerateReleaseNotesText(t *testing.T) {
+	opts := releaseNotesOptions{
+		Version:       "v1.4.0",
+		Date:          time.Date(2025, 1, 3, 9, 0, 0, 0, time.UTC),
+		GroupBy:       []string{GroupByComponent, GroupByType},
+		ExcludeLabels: []string{DefaultExcludeLabels},
+	}
+	response := TransitionCheckResponse{Tasks: []JiraTransitionResult{
+		{Key: "EV-10", Summary: "Sign release artifacts", Type: "Story", Components: []string{"Pipeline", "Security"}},
+		{Key: "EV-2", Summary: "Fix report encoding", Type: "bug", Components: []string{"Reports"}},
+	}}
+	assert.Equal(t, `Release v1.4.0 (2025-01-03)
+===========================
+
+Pipeline
+--------
+
+Features:
+  - Sign release artifacts (EV-10)
+
+Reports
+-------
+
+Bug Fixes:
+  - Fix report encoding (EV-2)
+
+Security
+--------
+
+Features:
+  - Sign release artifacts (EV-10)
+`, generateReleaseNotesText(buildReleaseNotes(response, opts)))
+
+	ungrouped := buildReleaseNotes(response, releaseNotesOptions{Version: "v1.4.0"})
+	assert.Equal(t, "Release v1.4.0\n==============\n\n- Fix report encoding (EV-2)\n- Sign release artifacts (EV-10)\n",
+		generateReleaseNotesText(ungrouped))
+}
+
+func TestReleaseNotesVersion(t *testing.T) {
+	tagged := &GitService{execCommand: func(args ...string) (string, error) { return "v2.0.0", nil }}
+	untagged := &GitService{execCommand: func(args ...string) (string, error) { return "", errors.New("no tags") }}
+
+	assert.Equal(t, "1.4.0", releaseNotesVersion("1.4.0", tagged))
+	assert.Equal(t, "v2.0.0", releaseNotesVersion("", tagged))
+	assert.Equal(t, UnreleasedVersion, releaseNotesVersion("", untagged))
+}
+
+func TestRunReleaseNotesCommand(t *testing.T) {
+	dir := t.TempDir()
+	inputFile := filepath.Join(dir, "evidence.json")
+	response := TransitionCheckResponse{Tasks: []JiraTransitionResult{
+		{Key: "EV-10", Summary: "Sign release artifacts", Type: "Story"},
+		{Key: "EV-2", Summary: "Fix report encoding", Type: "bug"},
+		{Key: "EV-3", Summary: "Bump linters", Type: "Task", Labels: []string{"No-Release-Notes"}},
+		{Key: "EV-404", Status: ErrorStatus, Type: ErrorType, Error: &TaskError{Code: ErrorCodeNotFound}},
+	}}
+	require.NoError(t, saveJiraResults(response, &AppConfig{OutputFile: inputFile}))
+
+	config := &AppConfig{Clock: func() time.Time { return time.Date(2025, 1, 3, 9, 0, 0, 0, time.UTC) }}
+	outputFile := filepath.Join(dir, "RELEASE_NOTES.txt")
+	flags := &FlagConfig{InputFile: inputFile, ReleaseNotesOutput: outputFile, Format: "text", Version: "1.4.0", GroupBy: "none",
+		ExcludeLabels: "none"}
+	require.NoError(t, runReleaseNotesCommand(flags, nil, config))
+
+	content, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.Equal(t, "Release 1.4.0 (2025-01-03)\n==========================\n\n"+
+		"- Fix report encoding (EV-2)\n- Bump linters (EV-3)\n- Sign release artifacts (EV-10)\n",
+		string(content))
+
+	t.Run("Invalid format", func(t *testing.T) {
+		err := runReleaseNotesCommand(&FlagConfig{InputFile: inputFile, Format: "html"}, nil, config)
+		assert.ErrorContains(t, err, "expected markdown or text")
+	})
+
+	t.Run("Invalid grouping", func(t *testing.T) {
+		err := runReleaseNotesCommand(&FlagConfig{InputFile: inputFile, GroupBy: "assignee"}, nil, config)
+		assert.ErrorContains(t, err, "group-by")
+	})
+}
+
+func TestReleaseNotesTitle(t *testing.T) {
+	date := time.Date(2025, 1, 3, 9, 0, 0, 0, time.UTC)
+	assert.Equal(t, "Release 1.4.0 (2025-01-03)", releaseNotes{Version: "1.4.0", Date: date}.title())
+	assert.Equal(t, "Unreleased (2025-01-03)", releaseNotes{Version: UnreleasedVersion, Date: date}.title())
+	assert.Equal(t, "Release 1.4.0", releaseNotes{Version: "1.4.0"}.title())
+}
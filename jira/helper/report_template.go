@@ -0,0 +1,229 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// defaultReportTemplate is the built-in markdown layout, used when no --template is given
+//
+//go:embed templates/report.md.tmpl
+var defaultReportTemplate string
+
+// ReportData is the data model report templates are executed with
+type ReportData struct {
+	Metadata ReportMetadata
+	// Tasks are the tickets in evidence order, or sorted with --sort-tasks
+	Tasks []JiraTransitionResult
+	// Aggregates holds the release-level flow metrics, nil for evidence without metrics
+	Aggregates *ReleaseMetrics
+	// Update lists the changes of evidence written by the update command, nil otherwise
+	Update *EvidenceUpdate
+	// StatusDistribution counts the tickets per status, most frequent first, ties by status name
+	StatusDistribution []StatusCount
+}
+
+// ReportMetadata describes the evidence a report is rendered from
+type ReportMetadata struct {
+	GeneratedAt   time.Time
+	SchemaVersion string
+	// AsOf is the --as-of instant the ticket state describes, empty for current state
+	AsOf string
+	// InputFile is the evidence file, empty when rendering in memory
+	InputFile string
+}
+
+// StatusCount is the number of tickets in one status
+type StatusCount struct {
+	Status string
+	Count  int
+}
+
+// ReportOptions configures report rendering and the helper functions available to report templates
+type ReportOptions struct {
+	DoneStatuses       []string
+	InProgressStatuses []string
+	// TaskOrder sorts the tickets before rendering; empty keeps evidence order
+	TaskOrder string
+	// GeneratedAt stamps the report; zero means the current time
+	GeneratedAt time.Time
+}
+
+// generatedAt returns the report timestamp
+func (o ReportOptions) generatedAt() time.Time {
+	if o.GeneratedAt.IsZero() {
+		return time.Now()
+	}
+	return o.GeneratedAt
+}
+
+// newReportData builds the template data of an evidence response
+func newReportData(response TransitionCheckResponse, inputFile string, generatedAt time.Time) ReportData {
+	data := ReportData{
+		Metadata: ReportMetadata{
+			GeneratedAt:   generatedAt,
+			SchemaVersion: response.SchemaVersion,
+			AsOf:          response.AsOf,
+			InputFile:     inputFile,
+		},
+		Tasks:      response.Tasks,
+		Aggregates: response.Aggregates,
+		Update:     response.Update,
+	}
+
+	index := make(map[string]int)
+	for _, task := range response.Tasks {
+		if i, ok := index[task.Status]; ok {
+			data.StatusDistribution[i].Count++
+			continue
+		}
+		index[task.Status] = len(data.StatusDistribution)
+		data.StatusDistribution = append(data.StatusDistribution, StatusCount{Status: task.Status, Count: 1})
+	}
+	// Independent of ticket order, so reordered evidence renders the same summary
+	sort.Slice(data.StatusDistribution, func(i, j int) bool {
+		a, b := data.StatusDistribution[i], data.StatusDistribution[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Status < b.Status
+	})
+	return data
+}
+
+// reportFuncs returns the helper functions available to report templates
+func reportFuncs(opts ReportOptions) template.FuncMap {
+	return template.FuncMap{
+		"formatDate":          formatDate,
+		"formatHours":         formatHours,
+		"formatOptionalHours": formatOptionalHours,
+		"statusBadge": func(status string) string {
+			return statusBadge(status, opts)
+		},
+		"linkify":      linkify,
+		"assigneeName": assigneeName,
+		"errorSummary": errorSummary,
+		"quote":        quote,
+		"join":         strings.Join,
+		"joinOrNone":   joinOrNone,
+		"add": func(a, b int) int {
+			return a + b
+		},
+	}
+}
+
+// parseReportTemplate parses a report template with the helper functions
+func parseReportTemplate(name, text string, opts ReportOptions) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(reportFuncs(opts)).Parse(text)
+	if err != nil {
+		return nil, &ValidationError{Field: "template", Value: name, Err: err}
+	}
+	return tmpl, nil
+}
+
+// loadReportTemplate reads a user-supplied template file, or returns the built-in layout for an empty path
+func loadReportTemplate(path string, opts ReportOptions) (*template.Template, error) {
+	if path == "" {
+		return parseReportTemplate("report.md.tmpl", defaultReportTemplate, opts)
+	}
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading template file: %v", err)
+	}
+	return parseReportTemplate(path, string(text), opts)
+}
+
+// renderReport executes a report template
+func renderReport(tmpl *template.Template, data ReportData) (string, error) {
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("error rendering template %s: %v", tmpl.Name(), err)
+	}
+	return sb.String(), nil
+}
+
+// Status categories used to badge and colour tickets
+const (
+	StatusCategoryDone       = "done"
+	StatusCategoryInProgress = "in-progress"
+	StatusCategoryError      = "error"
+	StatusCategoryOther      = "other"
+)
+
+// statusCategory classifies a status as done, in progress, error or anything else
+func statusCategory(status string, opts ReportOptions) string {
+	doneStatuses := opts.DoneStatuses
+	if doneStatuses == nil {
+		doneStatuses = parseFieldList(DefaultDoneStatuses)
+	}
+	inProgressStatuses := opts.InProgressStatuses
+	if inProgressStatuses == nil {
+		inProgressStatuses = parseFieldList(DefaultInProgressStatuses)
+	}
+
+	switch {
+	case status == ErrorStatus:
+		return StatusCategoryError
+	case statusIn(status, doneStatuses):
+		return StatusCategoryDone
+	case statusIn(status, inProgressStatuses):
+		return StatusCategoryInProgress
+	default:
+		return StatusCategoryOther
+	}
+}
+
+// statusBadge prefixes a status with an icon for its category
+func statusBadge(status string, opts ReportOptions) string {
+	switch statusCategory(status, opts) {
+	case StatusCategoryError:
+		return "❌ " + status
+	case StatusCategoryDone:
+		return "✅ " + status
+	case StatusCategoryInProgress:
+		return "🔄 " + status
+	default:
+		return "⏳ " + status
+	}
+}
+
+// linkify renders a markdown link, or the bare text when there is no URL
+func linkify(text, url string) string {
+	if url == "" {
+		return text
+	}
+	return fmt.Sprintf("[%s](%s)", text, url)
+}
+
+// assigneeName returns the assignee, or "Unassigned"
+func assigneeName(assignee *string) string {
+	if assignee == nil || *assignee == "" {
+		return "Unassigned"
+	}
+	return *assignee
+}
+
+// errorSummary describes a task error as its class, HTTP status and retryability
+func errorSummary(taskErr *TaskError) string {
+	if taskErr == nil {
+		return ""
+	}
+	summary := taskErr.Code
+	if taskErr.HTTPStatus != 0 {
+		summary = fmt.Sprintf("%s (HTTP %d)", summary, taskErr.HTTPStatus)
+	}
+	if taskErr.Retryable {
+		summary += ", retryable"
+	}
+	return summary
+}
+
+// quote renders text as a markdown blockquote
+func quote(text string) string {
+	return "> " + strings.ReplaceAll(text, "\n", "\n> ")
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultReportTemplate(t *testing.T) {
+	response := TransitionCheckResponse{
+		Tasks: []JiraTransitionResult{
+			{
+				Key:         "EV-1",
+				Link:        "https://example.atlassian.net/browse/EV-1",
+				Status:      "Done",
+				Type:        "Task",
+				Project:     "EV",
+				Priority:    "High",
+				Created:     "2025-01-01T10:00:00.000+0000",
+				Updated:     "2025-01-02T10:00:00.000+0000",
+				Assignee:    strPtr("John Doe"),
+				Reporter:    "Jane Smith",
+				Description: "First line\nSecond line",
+				Transitions: []Transition{
+					{FromStatus: "To Do", ToStatus: "Done", Author: "John Doe", TransitionTime: "2025-01-02T09:00:00.000+0000"},
+				},
+			},
+			{Key: "EV-2", Status: ErrorStatus, Type: ErrorType, Transitions: []Transition{},
+				Error: &TaskError{Code: ErrorCodeRateLimited, HTTPStatus: 429, Retryable: true}},
+		},
+	}
+	tmpl, err := loadReportTemplate("", ReportOptions{})
+	require.NoError(t, err)
+
+	markdown, err := renderReport(tmpl, newReportData(response, "", time.Date(2025, 1, 3, 12, 0, 0, 0, time.UTC)))
+	require.NoError(t, err)
+	assert.Equal(t, `# JIRA Tasks Report
+
+Generated on: 2025-01-03 12:00:00
+
+Total tasks: 2
+
+## Summary
+
+| Key | Status | Type | Priority | Assignee |
+|-----|--------|------|----------|----------|
+| [EV-1](https://example.atlassian.net/browse/EV-1) | Done | Task | High | John Doe |
+| EV-2 | Error | Error |  | Unassigned |
+
+## Task Details
+
+### 1. [EV-1](https://example.atlassian.net/browse/EV-1)
+
+**Basic Information:**
+- **Status:** Done
+- **Type:** Task
+- **Project:** EV
+- **Priority:** High
+
+**People:**
+- **Assignee:** John Doe
+- **Reporter:** Jane Smith
+
+**Dates:**
+- **Created:** 2025-01-01 10:00:00
+- **Updated:** 2025-01-02 10:00:00
+
+**Description:**
+> First line
+> Second line
+
+**Transition History:**
+
+| From Status | To Status | Author | Date |
+|-------------|-----------|--------|------|
+| To Do | Done | John Doe | 2025-01-02 09:00:00 |
+
+---
+
+### 2. EV-2
+
+**Basic Information:**
+- **Status:** Error
+- **Type:** Error
+- **Project:** 
+- **Priority:** 
+- **Error:** rate_limited (HTTP 429), retryable
+
+**People:**
+- **Assignee:** Unassigned
+- **Reporter:** 
+
+**Dates:**
+- **Created:** N/A
+- **Updated:** N/A
+
+---
+
+## Status Distribution
+
+| Status | Count |
+|--------|-------|
+| Done | 1 |
+| Error | 1 |
+`, markdown)
+}
+
+func TestCustomReportTemplate(t *testing.T) {
+	dir := t.TempDir()
+	inputFile := filepath.Join(dir, "evidence.json")
+	require.NoError(t, os.WriteFile(inputFile, []byte(`{"tasks": [
+		{"key": "EV-1", "link": "https://example.atlassian.net/browse/EV-1", "status": "Ready for Release", "description": "",
+		 "type": "Story", "project": "EV", "created": "", "updated": "2025-01-02T10:00:00.000+0000", "assignee": null,
+		 "reporter": "", "priority": "", "transitions": []},
+		{"key": "EV-2", "status": "In Progress", "description": "", "type": "Bug", "project": "EV", "created": "",
+		 "updated": "", "assignee": "Ann", "reporter": "", "priority": "", "transitions": []}
+	]}`), 0644))
+
+	templateFile := filepath.Join(dir, "release.md.tmpl")
+	require.NoError(t, os.WriteFile(templateFile, []byte(
+		"Release notes from {{.Metadata.InputFile}} (schema {{.Metadata.SchemaVersion}})\n"+
+			"{{range .Tasks}}- {{linkify .Key .Link}} {{statusBadge .Status}}, updated {{formatDate .Updated}}\n{{end}}"+
+			"{{range .StatusDistribution}}{{.Status}}={{.Count}};{{end}}\n"), 0644))
+
+	outputFile := filepath.Join(dir, "release.md")
+	opts := ReportOptions{DoneStatuses: []string{"Ready for Release"}, InProgressStatuses: []string{"In Progress"}}
+	require.NoError(t, GenerateMarkdownFromJSON(inputFile, outputFile, templateFile, opts))
+
+	content, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.Equal(t, "Release notes from "+inputFile+" (schema "+CurrentSchemaVersion+")\n"+
+		"- [EV-1](https://example.atlassian.net/browse/EV-1) ✅ Ready for Release, updated 2025-01-02 10:00:00\n"+
+		"- EV-2 🔄 In Progress, updated N/A\n"+
+		"In Progress=1;Ready for Release=1;\n", string(content))
+}
+
+func TestReportTemplateErrors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	t.Run("Syntax error", func(t *testing.T) {
+		_, err := loadReportTemplate(write("broken.tmpl", "{{range .Tasks}}"), ReportOptions{})
+		var validationErr *ValidationError
+		require.True(t, errors.As(err, &validationErr))
+		assert.Equal(t, "template", validationErr.Field)
+	})
+
+	t.Run("Unknown field", func(t *testing.T) {
+		tmpl, err := loadReportTemplate(write("unknown.tmpl", "{{.Tickets}}"), ReportOptions{})
+		require.NoError(t, err)
+		_, err = renderReport(tmpl, newReportData(TransitionCheckResponse{}, "", time.Now()))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "error rendering template")
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := loadReportTemplate(filepath.Join(dir, "missing.tmpl"), ReportOptions{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "error reading template file")
+	})
+}
+
+func TestStatusBadge(t *testing.T) {
+	tests := []struct {
+		status   string
+		opts     ReportOptions
+		expected string
+	}{
+		{status: "Done", expected: "✅ Done"},
+		{status: "closed", expected: "✅ closed"},
+		{status: "In Progress", expected: "🔄 In Progress"},
+		{status: "To Do", expected: "⏳ To Do"},
+		{status: ErrorStatus, expected: "❌ Error"},
+		{status: "Shipped", opts: ReportOptions{DoneStatuses: []string{"Shipped"}}, expected: "✅ Shipped"},
+		{status: "Done", opts: ReportOptions{DoneStatuses: []string{"Shipped"}}, expected: "⏳ Done"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, statusBadge(tt.status, tt.opts))
+		})
+	}
+}
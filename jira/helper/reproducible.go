@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SourceDateEpochEnv pins generated timestamps for reproducible output (see reproducible-builds.org)
+const SourceDateEpochEnv = "SOURCE_DATE_EPOCH"
+
+// Task orders for --sort-tasks
+const (
+	// TaskOrderNone keeps tickets in the order they were fetched or read
+	TaskOrderNone    = "none"
+	TaskOrderKey     = "key"
+	TaskOrderCreated = "created"
+	TaskOrderUpdated = "updated"
+	TaskOrderStatus  = "status"
+)
+
+// taskOrders lists the accepted --sort-tasks values
+var taskOrders = []string{TaskOrderNone, TaskOrderKey, TaskOrderCreated, TaskOrderUpdated, TaskOrderStatus}
+
+// parseTaskOrder validates a --sort-tasks value
+func parseTaskOrder(value string) (string, error) {
+	order := strings.ToLower(strings.TrimSpace(value))
+	if !containsString(taskOrders, order) {
+		return "", fmt.Errorf("expected one of: %s", strings.Join(taskOrders, ", "))
+	}
+	return order, nil
+}
+
+// parseSourceDateEpoch parses SOURCE_DATE_EPOCH, which is a count of seconds since the Unix epoch
+func parseSourceDateEpoch(value string) (time.Time, error) {
+	seconds, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || seconds < 0 {
+		return time.Time{}, fmt.Errorf("expected a non-negative number of seconds since the Unix epoch")
+	}
+	return time.Unix(seconds, 0).UTC(), nil
+}
+
+// loadClock returns a clock pinned to SOURCE_DATE_EPOCH, or nil to use the current time
+func loadClock() (func() time.Time, error) {
+	value := os.Getenv(SourceDateEpochEnv)
+	if value == "" {
+		return nil, nil
+	}
+	epoch, err := parseSourceDateEpoch(value)
+	if err != nil {
+		return nil, &ValidationError{Field: SourceDateEpochEnv, Value: value, Err: err}
+	}
+	return func() time.Time { return epoch }, nil
+}
+
+// now returns the configured clock's time, the current time unless SOURCE_DATE_EPOCH pins it
+func (c *AppConfig) now() time.Time {
+	if c.Clock != nil {
+		return c.Clock()
+	}
+	return time.Now()
+}
+
+// sortedTasks returns the tickets in the given order; ties, and TaskOrderNone, keep the input order
+func sortedTasks(tasks []JiraTransitionResult, order string) []JiraTransitionResult {
+	if order == "" || order == TaskOrderNone {
+		return tasks
+	}
+
+	sorted := append([]JiraTransitionResult(nil), tasks...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		switch order {
+		case TaskOrderCreated:
+			if c := compareTaskTimes(a.Created, b.Created); c != 0 {
+				return c < 0
+			}
+		case TaskOrderUpdated:
+			if c := compareTaskTimes(a.Updated, b.Updated); c != 0 {
+				return c < 0
+			}
+		case TaskOrderStatus:
+			if a.Status != b.Status {
+				return a.Status < b.Status
+			}
+		}
+		return compareJiraKeys(a.Key, b.Key) < 0
+	})
+	return sorted
+}
+
+// compareJiraKeys orders keys by project, then by issue number, so EV-9 sorts before EV-10
+func compareJiraKeys(a, b string) int {
+	aProject, aNumber := splitJiraKey(a)
+	bProject, bNumber := splitJiraKey(b)
+	if aProject != bProject {
+		return strings.Compare(aProject, bProject)
+	}
+	aValue, aErr := strconv.Atoi(aNumber)
+	bValue, bErr := strconv.Atoi(bNumber)
+	if aErr == nil && bErr == nil && aValue != bValue {
+		if aValue < bValue {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// splitJiraKey splits a key at its last dash into project and issue number
+func splitJiraKey(key string) (project, number string) {
+	if i := strings.LastIndex(key, "-"); i >= 0 {
+		return key[:i], key[i+1:]
+	}
+	return key, ""
+}
+
+// compareTaskTimes orders JIRA timestamps chronologically; missing or unparseable times sort last
+func compareTaskTimes(a, b string) int {
+	aTime, aErr := parseJiraTime(a)
+	bTime, bErr := parseJiraTime(b)
+	switch {
+	case aErr != nil && bErr != nil:
+		return 0
+	case aErr != nil:
+		return 1
+	case bErr != nil:
+		return -1
+	}
+	return aTime.Compare(bTime)
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTaskOrder(t *testing.T) {
+	order, err := parseTaskOrder(" Updated ")
+	require.NoError(t, err)
+	assert.Equal(t, TaskOrderUpdated, order)
+
+	_, err = parseTaskOrder("priority")
+	assert.EqualError(t, err, "expected one of: none, key, created, updated, status")
+}
+
+func TestParseSourceDateEpoch(t *testing.T) {
+	epoch, err := parseSourceDateEpoch("1735894800")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 3, 9, 0, 0, 0, time.UTC), epoch)
+
+	for _, value := range []string{"", "yesterday", "-1", "1735894800.5"} {
+		_, err := parseSourceDateEpoch(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestSortedTasks(t *testing.T) {
+	tasks := []JiraTransitionResult{
+		{Key: "EV-10", Status: "Done", Created: "2025-01-03T09:00:00.000+0000", Updated: "2025-01-04T09:00:00.000+0000"},
+		{Key: "OPS-2", Status: "In Progress", Created: "2025-01-01T09:00:00.000+0000", Updated: ""},
+		{Key: "EV-9", Status: "Done", Created: "2025-01-02T09:00:00.000+0000", Updated: "2025-01-05T09:00:00.000+0000"},
+		{Key: "EV-11", Status: ErrorStatus},
+	}
+	keys := func(tasks []JiraTransitionResult) []string {
+		var result []string
+		for _, task := range tasks {
+			result = append(result, task.Key)
+		}
+		return result
+	}
+
+	tests := []struct {
+		order    string
+		expected []string
+	}{
+		{order: TaskOrderNone, expected: []string{"EV-10", "OPS-2", "EV-9", "EV-11"}},
+		{order: "", expected: []string{"EV-10", "OPS-2", "EV-9", "EV-11"}},
+		{order: TaskOrderKey, expected: []string{"EV-9", "EV-10", "EV-11", "OPS-2"}},
+		// Tickets without a timestamp sort last, by key
+		{order: TaskOrderCreated, expected: []string{"OPS-2", "EV-9", "EV-10", "EV-11"}},
+		{order: TaskOrderUpdated, expected: []string{"EV-10", "EV-9", "EV-11", "OPS-2"}},
+		{order: TaskOrderStatus, expected: []string{"EV-9", "EV-10", "EV-11", "OPS-2"}},
+	}
+	for _, tt := range tests {
+		t.Run(getOrDefault(tt.order, "empty"), func(t *testing.T) {
+			assert.Equal(t, tt.expected, keys(sortedTasks(tasks, tt.order)))
+		})
+	}
+
+	// The input is left untouched
+	assert.Equal(t, []string{"EV-10", "OPS-2", "EV-9", "EV-11"}, keys(tasks))
+}
+
+func TestCompareJiraKeys(t *testing.T) {
+	assert.Equal(t, -1, compareJiraKeys("EV-9", "EV-10"))
+	assert.Equal(t, 1, compareJiraKeys("OPS-1", "EV-2"))
+	assert.Equal(t, 0, compareJiraKeys("EV-1", "EV-1"))
+	// Keys without an issue number fall back to text order
+	assert.Equal(t, -1, compareJiraKeys("EV-a", "EV-b"))
+}
+
+func TestLoadConfigReproducible(t *testing.T) {
+	clearConfigEnv(t)
+
+	config, err := LoadConfig(&FlagConfig{ExtractOnly: true}, []string{})
+	require.NoError(t, err)
+	assert.Nil(t, config.Clock)
+	assert.Equal(t, TaskOrderNone, config.TaskOrder)
+
+	// SOURCE_DATE_EPOCH pins the clock and sorts tickets by key
+	t.Setenv(SourceDateEpochEnv, "1735894800")
+	config, err = LoadConfig(&FlagConfig{ExtractOnly: true}, []string{})
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 3, 9, 0, 0, 0, time.UTC), config.now())
+	assert.Equal(t, TaskOrderKey, config.TaskOrder)
+
+	// An explicit order wins
+	t.Setenv("JIRA_SORT_TASKS", "updated")
+	config, err = LoadConfig(&FlagConfig{ExtractOnly: true}, []string{})
+	require.NoError(t, err)
+	assert.Equal(t, TaskOrderUpdated, config.TaskOrder)
+
+	config, err = LoadConfig(&FlagConfig{ExtractOnly: true, SortTasks: "none"}, []string{})
+	require.NoError(t, err)
+	assert.Equal(t, TaskOrderNone, config.TaskOrder)
+
+	_, err = LoadConfig(&FlagConfig{ExtractOnly: true, SortTasks: "priority"}, []string{})
+	assert.ErrorContains(t, err, "sort-tasks")
+
+	t.Setenv(SourceDateEpochEnv, "last tuesday")
+	_, err = LoadConfig(&FlagConfig{ExtractOnly: true}, []string{})
+	assert.ErrorContains(t, err, SourceDateEpochEnv)
+}
+
+func TestReproducibleReport(t *testing.T) {
+	dir := t.TempDir()
+	inputFile := filepath.Join(dir, "evidence.json")
+	require.NoError(t, os.WriteFile(inputFile, []byte(`{"tasks": [
+		{"key": "EV-2", "status": "In Progress", "transitions": []},
+		{"key": "EV-1", "status": "Done", "transitions": []},
+		{"key": "EV-3", "status": "Done", "transitions": []}
+	]}`), 0644))
+
+	opts := ReportOptions{TaskOrder: TaskOrderKey, GeneratedAt: time.Date(2025, 1, 3, 9, 0, 0, 0, time.UTC)}
+	render := func(name string) string {
+		outputFile := filepath.Join(dir, name)
+		require.NoError(t, GenerateMarkdownFromJSON(inputFile, outputFile, "", opts))
+		content, err := os.ReadFile(outputFile)
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	first := render("first.md")
+	assert.Equal(t, first, render("second.md"))
+	assert.Contains(t, first, "2025-01-03 09:00:00")
+	assert.Less(t, strings.Index(first, "EV-1"), strings.Index(first, "EV-2"))
+	assert.Less(t, strings.Index(first, "EV-2"), strings.Index(first, "EV-3"))
+}
+
+func TestSaveJiraResultsSortsTasks(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "evidence.json")
+	response := TransitionCheckResponse{Tasks: []JiraTransitionResult{
+		{Key: "EV-10", Status: "Done", Transitions: []Transition{}},
+		{Key: "EV-2", Status: "Done", Transitions: []Transition{}},
+	}}
+	require.NoError(t, saveJiraResults(response, &AppConfig{OutputFile: outputFile, TaskOrder: TaskOrderKey}))
+
+	saved, _, err := loadTransitionResponse(outputFile)
+	require.NoError(t, err)
+	require.Len(t, saved.Tasks, 2)
+	assert.Equal(t, "EV-2", saved.Tasks[0].Key)
+	assert.Equal(t, "EV-10", saved.Tasks[1].Key)
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SARIF log constants; results are uploaded to code scanning dashboards such as GitHub's
+const (
+	SARIFVersion   = "2.1.0"
+	sarifSchemaURI = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifToolName  = "jira-helper"
+	// sarifFingerprint identifies a finding across runs, so dashboards track it instead of reopening it
+	sarifFingerprint = "jiraComplianceFinding/v1"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool                     sarifTool             `json:"tool"`
+	VersionControlProvenance []sarifVersionControl `json:"versionControlProvenance,omitempty"`
+	Results                  []sarifResult         `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+// sarifVersionControl ties a run to the repository revision it checked
+type sarifVersionControl struct {
+	RepositoryURI string `json:"repositoryUri"`
+	RevisionID    string `json:"revisionId,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID              string                `json:"ruleId"`
+	RuleIndex           int                   `json:"ruleIndex"`
+	Level               string                `json:"level"`
+	Message             sarifMessage          `json:"message"`
+	Locations           []sarifLocation       `json:"locations"`
+	PartialFingerprints map[string]string     `json:"partialFingerprints"`
+	Properties          sarifResultProperties `json:"properties"`
+}
+
+// sarifResultProperties ties a finding to its ticket and to the commits it concerns
+type sarifResultProperties struct {
+	JiraKey    string   `json:"jiraKey,omitempty"`
+	CommitSHAs []string `json:"commitShas,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// sarifRules returns every policy rule sorted by ID, so rule indexes stay stable across runs
+func sarifRules() []sarifRule {
+	ids := make([]string, 0, len(policyRuleDescriptions))
+	for id := range policyRuleDescriptions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	rules := make([]sarifRule, len(ids))
+	for i, id := range ids {
+		rules[i] = sarifRule{
+			ID:                   id,
+			ShortDescription:     sarifMessage{Text: policyRuleDescriptions[id]},
+			DefaultConfiguration: sarifConfiguration{Level: "error"},
+		}
+	}
+	return rules
+}
+
+// sarifArtifact locates the evidence file for code scanning: relative to the repository root under %SRCROOT%,
+// or by an absolute file URI when it lies outside the repository or there is none
+func sarifArtifact(inputFile, repositoryRoot string) sarifArtifactLocation {
+	resolve := func(path string) string {
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			return resolved
+		}
+		return path
+	}
+
+	path, err := filepath.Abs(inputFile)
+	if err != nil {
+		return sarifArtifactLocation{URI: (&url.URL{Path: filepath.ToSlash(inputFile)}).EscapedPath()}
+	}
+	path = resolve(path)
+	if repositoryRoot != "" {
+		rel, err := filepath.Rel(resolve(repositoryRoot), path)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return sarifArtifactLocation{URI: (&url.URL{Path: filepath.ToSlash(rel)}).EscapedPath(), URIBaseID: "%SRCROOT%"}
+		}
+	}
+
+	slashed := filepath.ToSlash(path)
+	if !strings.HasPrefix(slashed, "/") {
+		slashed = "/" + slashed // Windows drive letters
+	}
+	return sarifArtifactLocation{URI: (&url.URL{Scheme: "file", Path: slashed}).String()}
+}
+
+// newSARIFLog converts gate violations into SARIF results. Ticket findings carry the SHAs of the checked commits
+// that reference the ticket; commit findings carry the commit itself. SARIF results need a file location, so
+// every finding points at the evidence file it was derived from, located against repositoryRoot. repositoryURI,
+// when known, ties the run to the newest checked commit.
+func newSARIFLog(report *GateReport, inputFile, repositoryRoot, repositoryURI string) sarifLog {
+	rules := sarifRules()
+	ruleIndex := make(map[string]int, len(rules))
+	for i, rule := range rules {
+		ruleIndex[rule.ID] = i
+	}
+
+	location := []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifact(inputFile, repositoryRoot),
+		Region:           sarifRegion{StartLine: 1},
+	}}}
+
+	results := []sarifResult{}
+	addResult := func(violation PolicyViolation, properties sarifResultProperties, subject string) {
+		results = append(results, sarifResult{
+			RuleID:              violation.RuleID,
+			RuleIndex:           ruleIndex[violation.RuleID],
+			Level:               "error",
+			Message:             sarifMessage{Text: violation.Message},
+			Locations:           location,
+			PartialFingerprints: map[string]string{sarifFingerprint: violation.RuleID + ":" + subject},
+			Properties:          properties,
+		})
+	}
+
+	for _, result := range report.Results {
+		for _, violation := range result.Violations {
+			addResult(violation, sarifResultProperties{
+				JiraKey:    result.Task.Key,
+				CommitSHAs: report.commitsReferencing(result.Task.Key),
+			}, result.Task.Key)
+		}
+	}
+	for _, violation := range report.Violations {
+		var properties sarifResultProperties
+		subject := "evidence"
+		if violation.Commit != "" {
+			properties.CommitSHAs = []string{violation.Commit}
+			subject = violation.Commit
+		}
+		addResult(violation, properties, subject)
+	}
+
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: sarifToolName, Rules: rules}},
+		Results: results,
+	}
+	if repositoryURI != "" {
+		provenance := sarifVersionControl{RepositoryURI: repositoryURI}
+		if len(report.Commits) > 0 {
+			provenance.RevisionID = report.Commits[0].SHA
+		}
+		run.VersionControlProvenance = []sarifVersionControl{provenance}
+	}
+
+	return sarifLog{Schema: sarifSchemaURI, Version: SARIFVersion, Runs: []sarifRun{run}}
+}
+
+// writeSARIFReport writes gate violations as a SARIF log
+func writeSARIFReport(report *GateReport, inputFile, repositoryRoot, repositoryURI, outputFile string) error {
+	data, err := json.MarshalIndent(newSARIFLog(report, inputFile, repositoryRoot, repositoryURI), "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling SARIF: %v", err)
+	}
+	if err := writeToFile(outputFile, append(data, '\n')); err != nil {
+		return fmt.Errorf("error writing to file: %v", err)
+	}
+	return nil
+}
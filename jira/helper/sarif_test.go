@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSARIFRules(t *testing.T) {
+	rules := sarifRules()
+	require.Len(t, rules, len(policyRuleDescriptions))
+
+	var ids []string
+	for _, rule := range rules {
+		ids = append(ids, rule.ID)
+		assert.Equal(t, policyRuleDescriptions[rule.ID], rule.ShortDescription.Text)
+		assert.Equal(t, "error", rule.DefaultConfiguration.Level)
+	}
+	assert.Equal(t, []string{RuleUntrackedCommit, RuleFetchError, RuleNoTasks, RuleSeparationOfDuties, RuleStatusNotAllowed}, ids)
+}
+
+func TestSARIFArtifact(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	repositoryRoot := filepath.Join(dir, "repo")
+	inside := filepath.Join(repositoryRoot, "jira", "helper", "build output", "evidence.json")
+	outside := filepath.Join(dir, "evidence.json")
+	for _, file := range []string{inside, outside} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+		require.NoError(t, os.WriteFile(file, []byte("{}"), 0644))
+	}
+	fileURI := "file://" + filepath.ToSlash(outside)
+	if !strings.HasPrefix(filepath.ToSlash(outside), "/") {
+		fileURI = "file:///" + filepath.ToSlash(outside)
+	}
+
+	tests := []struct {
+		name           string
+		inputFile      string
+		repositoryRoot string
+		expected       sarifArtifactLocation
+	}{
+		{
+			name:           "Absolute path inside the repository",
+			inputFile:      inside,
+			repositoryRoot: repositoryRoot,
+			expected:       sarifArtifactLocation{URI: "jira/helper/build%20output/evidence.json", URIBaseID: "%SRCROOT%"},
+		},
+		{
+			name:           "Absolute path outside the repository",
+			inputFile:      outside,
+			repositoryRoot: repositoryRoot,
+			expected:       sarifArtifactLocation{URI: fileURI},
+		},
+		{
+			name:      "Without a repository",
+			inputFile: outside,
+			expected:  sarifArtifactLocation{URI: fileURI},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, sarifArtifact(tt.inputFile, tt.repositoryRoot))
+		})
+	}
+
+	t.Run("Path relative to another directory", func(t *testing.T) {
+		cwd, err := os.Getwd()
+		require.NoError(t, err)
+		// ../helper leaves the working directory, but not the repository
+		location := sarifArtifact(filepath.Join("..", filepath.Base(cwd), "sarif.go"), filepath.Dir(filepath.Dir(cwd)))
+		assert.Equal(t, sarifArtifactLocation{URI: filepath.Base(filepath.Dir(cwd)) + "/" + filepath.Base(cwd) + "/sarif.go",
+			URIBaseID: "%SRCROOT%"}, location)
+	})
+}
+
+func TestNewSARIFLog(t *testing.T) {
+	report := evaluatePolicy(TransitionCheckResponse{Tasks: []JiraTransitionResult{
+		{Key: "EV-1", Status: "Done"},
+		{Key: "EV-2", Status: "In Review"},
+		{Key: "EV-3", Status: ErrorStatus, Error: &TaskError{Code: ErrorCodeNotFound, Message: "Issue does not exist"}},
+	}}, GatePolicy{AllowedStatuses: []string{"Done"}})
+	checkCommits(report, []GitCommit{
+		{SHA: "aaa111", Subject: "EV-2 Fix login", JiraIDs: []string{"EV-2"}},
+		{SHA: "bbb222", Subject: "Bump dependencies"},
+		{SHA: "ccc333", Subject: "EV-1 EV-2 Tidy up", JiraIDs: []string{"EV-1", "EV-2"}},
+	})
+	root := t.TempDir()
+	inputFile := filepath.Join(root, "build", "evidence.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(inputFile), 0755))
+	require.NoError(t, os.WriteFile(inputFile, []byte("{}"), 0644))
+	log := newSARIFLog(report, inputFile, root, "https://github.com/org/repo")
+	assert.Equal(t, SARIFVersion, log.Version)
+	require.Len(t, log.Runs, 1)
+
+	run := log.Runs[0]
+	assert.Equal(t, sarifToolName, run.Tool.Driver.Name)
+	assert.Equal(t, []sarifVersionControl{{RepositoryURI: "https://github.com/org/repo", RevisionID: "aaa111"}}, run.VersionControlProvenance)
+	require.Len(t, run.Results, 3)
+
+	// Ticket findings carry the commits that reference the ticket
+	notDone := run.Results[0]
+	assert.Equal(t, RuleStatusNotAllowed, notDone.RuleID)
+	assert.Equal(t, RuleStatusNotAllowed, run.Tool.Driver.Rules[notDone.RuleIndex].ID)
+	assert.Equal(t, "EV-2 is 'In Review', expected one of: Done", notDone.Message.Text)
+	assert.Equal(t, sarifResultProperties{JiraKey: "EV-2", CommitSHAs: []string{"aaa111", "ccc333"}}, notDone.Properties)
+	assert.Equal(t, map[string]string{sarifFingerprint: "jira/status-not-allowed:EV-2"}, notDone.PartialFingerprints)
+	assert.Equal(t, sarifArtifactLocation{URI: "build/evidence.json", URIBaseID: "%SRCROOT%"},
+		notDone.Locations[0].PhysicalLocation.ArtifactLocation)
+
+	fetchError := run.Results[1]
+	assert.Equal(t, RuleFetchError, fetchError.RuleID)
+	assert.Equal(t, sarifResultProperties{JiraKey: "EV-3"}, fetchError.Properties)
+
+	// Commit findings carry the commit itself
+	untracked := run.Results[2]
+	assert.Equal(t, RuleUntrackedCommit, untracked.RuleID)
+	assert.Equal(t, sarifResultProperties{CommitSHAs: []string{"bbb222"}}, untracked.Properties)
+	assert.Equal(t, map[string]string{sarifFingerprint: "git/untracked-commit:bbb222"}, untracked.PartialFingerprints)
+
+	t.Run("Without a repository or commits", func(t *testing.T) {
+		report := evaluatePolicy(TransitionCheckResponse{}, GatePolicy{RequireTasks: true})
+		log := newSARIFLog(report, "evidence.json", "", "")
+		run := log.Runs[0]
+		assert.Empty(t, run.VersionControlProvenance)
+		require.Len(t, run.Results, 1)
+		assert.Equal(t, RuleNoTasks, run.Results[0].RuleID)
+		assert.Equal(t, map[string]string{sarifFingerprint: "jira/no-tasks:evidence"}, run.Results[0].PartialFingerprints)
+	})
+}
+
+func TestWriteSARIFReport(t *testing.T) {
+	report := evaluatePolicy(TransitionCheckResponse{Tasks: []JiraTransitionResult{{Key: "EV-2", Status: "In Review"}}},
+		GatePolicy{AllowedStatuses: []string{"Done"}})
+	checkCommits(report, []GitCommit{
+		{SHA: "aaa111", Subject: "EV-2 Fix login", JiraIDs: []string{"EV-2"}},
+		{SHA: "bbb222", Subject: "Bump dependencies"},
+	})
+	outputFile := filepath.Join(t.TempDir(), "jira.sarif")
+	require.NoError(t, writeSARIFReport(report, "evidence.json", "", "", outputFile))
+
+	data, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+
+	var document map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &document))
+	assert.Equal(t, sarifSchemaURI, document["$schema"])
+	assert.Equal(t, "2.1.0", document["version"])
+	assert.Contains(t, string(data), `"ruleId": "git/untracked-commit"`)
+	assert.Contains(t, string(data), `"commitShas": [`)
+
+	t.Run("Passing gate writes an empty result list", func(t *testing.T) {
+		report := evaluatePolicy(TransitionCheckResponse{Tasks: []JiraTransitionResult{{Key: "EV-1", Status: "Done"}}}, GatePolicy{})
+		require.NoError(t, writeSARIFReport(report, "evidence.json", "", "", outputFile))
+		data, err := os.ReadFile(outputFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"results": []`)
+	})
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Constants for the predicate schema
+const (
+	// CurrentSchemaVersion is the version written to schema_version; bump it whenever the predicate format changes
+	CurrentSchemaVersion = "1.5.0"
+	PredicateType        = "http://atlassian.com/jira/issues/v1"
+	SchemaCommand        = "schema"
+	SchemaFile           = "schema/transition_check_response.schema.json"
+)
+
+// embeddedSchema is the published JSON Schema of TransitionCheckResponse.
+// Regenerate it with `make schema` after changing the models; a unit test keeps it in sync.
+//
+//go:embed schema/transition_check_response.schema.json
+var embeddedSchema []byte
+
+// generateSchema builds the JSON Schema of TransitionCheckResponse from its struct definition
+func generateSchema() map[string]interface{} {
+	defs := make(map[string]interface{})
+	root := schemaForStruct(reflect.TypeOf(TransitionCheckResponse{}), defs)
+
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["$id"] = PredicateType + "/schema/" + CurrentSchemaVersion
+	root["title"] = "JIRA issues evidence predicate"
+	root["$defs"] = defs
+
+	// Pin the version so consumers can tell which shape they are reading
+	props := root["properties"].(map[string]interface{})
+	props["schema_version"] = map[string]interface{}{"type": "string", "const": CurrentSchemaVersion}
+	return root
+}
+
+// generateSchemaJSON returns the generated schema as indented JSON with a trailing newline
+func generateSchemaJSON() ([]byte, error) {
+	data, err := json.MarshalIndent(generateSchema(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// schemaForType returns the schema of a Go type, registering named structs in defs
+func schemaForType(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaForType(t.Elem(), defs)
+	case reflect.Struct:
+		name := t.Name()
+		if _, ok := defs[name]; !ok {
+			defs[name] = nil // Reserve the name to stop recursion
+			defs[name] = schemaForStruct(t, defs)
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + name}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": schemaForType(t.Elem(), defs)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaForType(t.Elem(), defs)}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// schemaForStruct returns an object schema with one property per JSON-tagged field.
+// Fields without omitempty are required; pointers, slices and maps without omitempty may be null.
+func schemaForStruct(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := []string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		omitEmpty := strings.Contains(options, "omitempty")
+
+		schema := schemaForType(field.Type, defs)
+		if !omitEmpty {
+			required = append(required, name)
+			switch field.Type.Kind() {
+			case reflect.Ptr, reflect.Slice, reflect.Map:
+				schema = nullable(schema)
+			}
+		}
+		properties[name] = schema
+	}
+
+	sort.Strings(required)
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+// nullable widens a schema to also accept null
+func nullable(schema map[string]interface{}) map[string]interface{} {
+	if typ, ok := schema["type"].(string); ok {
+		widened := make(map[string]interface{}, len(schema))
+		for k, v := range schema {
+			widened[k] = v
+		}
+		widened["type"] = []interface{}{typ, "null"}
+		return widened
+	}
+	return map[string]interface{}{"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}}}
+}
+
+// validateAgainstSchema validates JSON data against the embedded predicate schema
+func validateAgainstSchema(data []byte) error {
+	var schema map[string]interface{}
+	if err := json.Unmarshal(embeddedSchema, &schema); err != nil {
+		return fmt.Errorf("invalid embedded schema: %v", err)
+	}
+
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("invalid JSON: %v", err)
+	}
+
+	v := &schemaValidator{root: schema}
+	v.validate(document, schema, "$")
+	if len(v.problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(v.problems, "; "))
+	}
+	return nil
+}
+
+// schemaValidator checks a document against the JSON Schema subset produced by generateSchema:
+// type, const, properties, required, additionalProperties, items, anyOf and local $refs.
+type schemaValidator struct {
+	root     map[string]interface{}
+	problems []string
+}
+
+func (v *schemaValidator) fail(path, format string, args ...interface{}) {
+	v.problems = append(v.problems, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *schemaValidator) validate(value interface{}, schema map[string]interface{}, path string) {
+	if ref, ok := schema["$ref"].(string); ok {
+		resolved, err := v.resolve(ref)
+		if err != nil {
+			v.fail(path, "%v", err)
+			return
+		}
+		v.validate(value, resolved, path)
+		return
+	}
+
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		for _, option := range anyOf {
+			optionSchema, _ := option.(map[string]interface{})
+			probe := &schemaValidator{root: v.root}
+			probe.validate(value, optionSchema, path)
+			if len(probe.problems) == 0 {
+				return
+			}
+		}
+		v.fail(path, "does not match any allowed schema")
+		return
+	}
+
+	if typ, ok := schema["type"]; ok && !matchesType(value, typ) {
+		v.fail(path, "expected %v, got %s", typ, jsonTypeName(value))
+		return
+	}
+
+	if constant, ok := schema["const"]; ok && value != constant {
+		v.fail(path, "expected %v, got %v", constant, value)
+	}
+
+	switch val := value.(type) {
+	case map[string]interface{}:
+		v.validateObject(val, schema, path)
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range val {
+				v.validate(item, items, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	}
+}
+
+func (v *schemaValidator) validateObject(obj map[string]interface{}, schema map[string]interface{}, path string) {
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			if _, present := obj[name.(string)]; !present {
+				v.fail(path, "missing required property '%s'", name)
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		childPath := path + "." + key
+		if propSchema, ok := properties[key].(map[string]interface{}); ok {
+			v.validate(obj[key], propSchema, childPath)
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				v.fail(path, "unexpected property '%s'", key)
+			}
+		case map[string]interface{}:
+			v.validate(obj[key], additional, childPath)
+		}
+	}
+}
+
+func (v *schemaValidator) resolve(ref string) (map[string]interface{}, error) {
+	const prefix = "#/$defs/"
+	if !strings.HasPrefix(ref, prefix) {
+		return nil, fmt.Errorf("unsupported $ref '%s'", ref)
+	}
+	defs, _ := v.root["$defs"].(map[string]interface{})
+	resolved, ok := defs[strings.TrimPrefix(ref, prefix)].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unresolved $ref '%s'", ref)
+	}
+	return resolved, nil
+}
+
+// matchesType checks a decoded JSON value against a schema "type" (string or list of strings)
+func matchesType(value interface{}, typ interface{}) bool {
+	switch t := typ.(type) {
+	case string:
+		actual := jsonTypeName(value)
+		return actual == t || (t == "number" && actual == "integer")
+	case []interface{}:
+		for _, option := range t {
+			if matchesType(value, option) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonTypeName returns the JSON Schema type name of a decoded JSON value
+func jsonTypeName(value interface{}) string {
+	switch val := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if val == float64(int64(val)) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// runSchemaMode prints the embedded predicate schema
+func runSchemaMode() error {
+	fmt.Print(string(embeddedSchema))
+	return nil
+}
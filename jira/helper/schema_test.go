@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateSchema = flag.Bool("update", false, "regenerate the embedded predicate schema")
+
+// TestEmbeddedSchemaUpToDate fails when the models change without regenerating the schema.
+// Run `make schema` (go test -run TestEmbeddedSchemaUpToDate -update) to regenerate it.
+func TestEmbeddedSchemaUpToDate(t *testing.T) {
+	generated, err := generateSchemaJSON()
+	require.NoError(t, err)
+
+	if *updateSchema {
+		require.NoError(t, os.WriteFile(SchemaFile, generated, 0644))
+		return
+	}
+
+	assert.Equal(t, string(generated), string(embeddedSchema),
+		"embedded schema is stale; run `make schema` and bump CurrentSchemaVersion if the format changed")
+}
+
+func TestGenerateSchema(t *testing.T) {
+	schema := generateSchema()
+
+	assert.Equal(t, PredicateType+"/schema/"+CurrentSchemaVersion, schema["$id"])
+
+	props := schema["properties"].(map[string]interface{})
+	assert.Equal(t, CurrentSchemaVersion, props["schema_version"].(map[string]interface{})["const"])
+	assert.Equal(t, []string{"schema_version", "tasks"}, schema["required"])
+
+	defs := schema["$defs"].(map[string]interface{})
+	for _, name := range []string{"JiraTransitionResult", "Transition", "FieldChange", "TaskMetrics", "TaskError"} {
+		assert.Contains(t, defs, name)
+	}
+
+	task := defs["JiraTransitionResult"].(map[string]interface{})
+	taskProps := task["properties"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"string", "null"}, taskProps["assignee"].(map[string]interface{})["type"])
+	assert.NotContains(t, task["required"], "link")
+	assert.Contains(t, task["required"], "key")
+}
+
+func TestValidateAgainstSchema(t *testing.T) {
+	valid := TransitionCheckResponse{
+		SchemaVersion: CurrentSchemaVersion,
+		Tasks: []JiraTransitionResult{
+			{
+				Key:         "EV-1",
+				Status:      "Done",
+				Assignee:    strPtr("Alice"),
+				Transitions: []Transition{{FromStatus: "To Do", ToStatus: "Done"}},
+				Metrics:     &TaskMetrics{TimeInStatus: []StatusDuration{{Status: "To Do", Hours: 1.5}}},
+				FieldValues: map[string]string{"Sprint": "Sprint 4"},
+			},
+			{
+				Key:    "EV-2",
+				Status: ErrorStatus,
+				Error:  &TaskError{Code: ErrorCodeNotFound, HTTPStatus: 404},
+			},
+		},
+	}
+	validJSON, err := json.Marshal(valid)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		data          string
+		errorContains []string
+	}{
+		{
+			name: "Valid response",
+			data: string(validJSON),
+		},
+		{
+			name:          "Wrong schema version",
+			data:          `{"schema_version": "0.9", "tasks": []}`,
+			errorContains: []string{"$.schema_version"},
+		},
+		{
+			name:          "Missing tasks",
+			data:          `{"schema_version": "` + CurrentSchemaVersion + `"}`,
+			errorContains: []string{"missing required property 'tasks'"},
+		},
+		{
+			name:          "Unexpected property",
+			data:          `{"schema_version": "` + CurrentSchemaVersion + `", "tasks": [], "extra": 1}`,
+			errorContains: []string{"unexpected property 'extra'"},
+		},
+		{
+			name: "Wrong field type in task",
+			data: `{"schema_version": "` + CurrentSchemaVersion + `", "tasks": [{"key": 5, "status": "", "description": "",
+				"type": "", "project": "", "created": "", "updated": "", "assignee": null, "reporter": "",
+				"priority": "", "transitions": []}]}`,
+			errorContains: []string{"$.tasks[0].key: expected string"},
+		},
+		{
+			name:          "Invalid JSON",
+			data:          `{`,
+			errorContains: []string{"invalid JSON"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAgainstSchema([]byte(tt.data))
+			if len(tt.errorContains) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, part := range tt.errorContains {
+				assert.Contains(t, err.Error(), part)
+			}
+		})
+	}
+}
+
+func TestRunSchemaMode(t *testing.T) {
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := runSchemaMode()
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var sb strings.Builder
+	buf := make([]byte, 4096)
+	for {
+		n, readErr := r.Read(buf)
+		sb.Write(buf[:n])
+		if readErr != nil {
+			break
+		}
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, string(embeddedSchema), sb.String())
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// runUpdateCommand refreshes a previous evidence file, fetching only new tickets and tickets JIRA updated since
+func runUpdateCommand(flags *FlagConfig, args []string, config *AppConfig) error {
+	// Unlike report and gate, -o names the new file, so it is not a fallback for the previous one
+	previousFile := getOrDefault(flags.InputFile, config.evidenceFile())
+
+	fmt.Println("=== JIRA Evidence Update ===")
+	fmt.Printf("Previous JSON file: %s\n", previousFile)
+	fmt.Printf("Output File: %s\n", config.OutputFile)
+	fmt.Println("")
+
+	previous, report, err := loadTransitionResponse(previousFile)
+	if err != nil {
+		return err
+	}
+	if report.FromVersion != report.ToVersion {
+		fmt.Printf("Upgraded previous evidence from schema %s to %s\n", report.FromVersion, report.ToVersion)
+	}
+	if err := checkUpdateAsOf(previous, config); err != nil {
+		return err
+	}
+	// Kept tickets are requested by key, so the keys of the previous file are checked like arguments
+	previousKeys := make([]string, 0, len(previous.Tasks))
+	for _, task := range previous.Tasks {
+		previousKeys = append(previousKeys, task.Key)
+	}
+	if err := checkJiraIDArgs(previousKeys, config.JIRAIDRegex); err != nil {
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) {
+			validationErr.File = previousFile
+		}
+		return err
+	}
+
+	jiraIDs := args
+	if err := checkJiraIDArgs(args, config.JIRAIDRegex); err != nil {
+		return err
+	}
+	if flags.Commit != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("unexpected arguments with --commit: %s", strings.Join(args, " "))
+		}
+		config.StartCommit = flags.Commit
+		if jiraIDs, err = extractCommitJiraIDs(config); err != nil {
+			return err
+		}
+	}
+	if flags.Prune && len(jiraIDs) == 0 {
+		return fmt.Errorf("--prune needs the current JIRA IDs (arguments or --commit), otherwise every ticket is removed")
+	}
+	config.JIRAIDs = jiraIDs
+
+	response, err := runJiraSession(config, config.now(), func(client *JiraClient) TransitionCheckResponse {
+		return client.UpdateJiraDetails(previous, jiraIDs, flags.Prune)
+	})
+	if err != nil {
+		return err
+	}
+	printEvidenceUpdate(response.Update)
+
+	if err := saveJiraResults(response, config); err != nil {
+		return err
+	}
+	return exitCodeForErrors(response, config.ErrorExitCodes)
+}
+
+// checkUpdateAsOf refuses to mix tickets reconstructed at different instants.
+// History after the previous --as-of instant was dropped, so those tickets cannot be reused for another one.
+func checkUpdateAsOf(previous TransitionCheckResponse, config *AppConfig) error {
+	if previous.AsOf == "" {
+		return nil
+	}
+	previousAsOf, err := parseJiraTime(previous.AsOf)
+	if err == nil && !config.AsOf.IsZero() && previousAsOf.Equal(config.AsOf) {
+		return nil
+	}
+	return &ValidationError{Field: "as-of", Value: previous.AsOf,
+		Err: fmt.Errorf("previous evidence describes ticket state as of this instant; pass the same --as-of")}
+}
+
+// extractCommitJiraIDs returns the JIRA IDs referenced by config.StartCommit (or the range up to HEAD)
+func extractCommitJiraIDs(config *AppConfig) ([]string, error) {
+	git := NewGitService()
+	if err := git.CheckRepository(); err != nil {
+		return nil, err
+	}
+	_, _, currentJiraID, err := git.GetBranchInfo()
+	if err != nil {
+		return nil, fmt.Errorf("error getting branch info: %v", err)
+	}
+	if err := git.ValidateHEAD(); err != nil {
+		return nil, err
+	}
+
+	jiraIDs, err := git.ExtractJiraIDs(config.StartCommit, config.JIRAIDRegex, currentJiraID, config.SingleCommit)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting JIRA IDs: %v", err)
+	}
+	fmt.Printf("Found JIRA IDs: %s\n", strings.Join(jiraIDs, ", "))
+	return jiraIDs, nil
+}
+
+// UpdateJiraDetails builds new evidence from previous evidence and the current JIRA IDs.
+// Previous tickets are kept in their order, followed by new ones; with prune, previous tickets missing from
+// jiraIDs are dropped. A kept ticket is refetched only when JIRA reports a different updated timestamp or when
+// it could not be fetched before, so the full changelog is downloaded for new and stale tickets only.
+func (jc *JiraClient) UpdateJiraDetails(previous TransitionCheckResponse, jiraIDs []string, prune bool) TransitionCheckResponse {
+	update := &EvidenceUpdate{Added: []string{}, Removed: []string{}, Changed: []string{}, Unchanged: []string{}}
+	response := TransitionCheckResponse{
+		SchemaVersion: CurrentSchemaVersion,
+		Tasks:         make([]JiraTransitionResult, 0, len(previous.Tasks)+len(jiraIDs)),
+		Update:        update,
+	}
+
+	wanted := make(map[string]bool, len(jiraIDs))
+	for _, jiraID := range jiraIDs {
+		wanted[strings.ToUpper(jiraID)] = true
+	}
+
+	seen := make(map[string]bool, len(previous.Tasks))
+	for _, task := range previous.Tasks {
+		key := strings.ToUpper(task.Key)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if prune && !wanted[key] {
+			update.Removed = append(update.Removed, task.Key)
+			continue
+		}
+		if jc.isUnchanged(task) {
+			update.Unchanged = append(update.Unchanged, task.Key)
+			response.Tasks = append(response.Tasks, task)
+			continue
+		}
+		update.Changed = append(update.Changed, task.Key)
+		response.Tasks = append(response.Tasks, jc.fetchSingleJiraDetail(task.Key))
+	}
+
+	for _, jiraID := range jiraIDs {
+		key := strings.ToUpper(jiraID)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		update.Added = append(update.Added, jiraID)
+		response.Tasks = append(response.Tasks, jc.fetchSingleJiraDetail(jiraID))
+	}
+
+	return response
+}
+
+// isUnchanged reports whether a previously fetched ticket still has the updated timestamp JIRA reports for it.
+// Failed lookups count as changed, so the refetch records the current error instead of outdated data.
+func (jc *JiraClient) isUnchanged(task JiraTransitionResult) bool {
+	if task.Status == ErrorStatus || task.Updated == "" {
+		return false
+	}
+	previousUpdated, err := parseJiraTime(task.Updated)
+	if err != nil {
+		return false
+	}
+
+	updated, err := jc.fetchIssueUpdated(context.Background(), task.Key)
+	if err != nil {
+		return false
+	}
+	current, err := time.Parse(time.RFC3339Nano, updated)
+	return err == nil && current.Equal(previousUpdated)
+}
+
+// printEvidenceUpdate prints which tickets were added, removed, refetched or reused
+func printEvidenceUpdate(update *EvidenceUpdate) {
+	if update == nil {
+		return
+	}
+	for _, entry := range []struct {
+		label string
+		keys  []string
+	}{
+		{"Added", update.Added},
+		{"Removed", update.Removed},
+		{"Changed", update.Changed},
+		{"Unchanged", update.Unchanged},
+	} {
+		if len(entry.keys) == 0 {
+			fmt.Printf("%s: none\n", entry.label)
+			continue
+		}
+		fmt.Printf("%s: %s\n", entry.label, strings.Join(entry.keys, ", "))
+	}
+	fmt.Println("")
+}
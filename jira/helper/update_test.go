@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newUpdateFixtures starts a fake JIRA serving EV-1 to EV-3 from fixtures the test can rewrite
+func newUpdateFixtures(t *testing.T) (string, *FakeJira, *JiraClient) {
+	t.Helper()
+	fixtures := t.TempDir()
+	writeIssueFixture(t, fixtures, "EV-1", "Done", "2024-01-02T10:00:00.000+0000")
+	writeIssueFixture(t, fixtures, "EV-2", "In Progress", "2024-01-03T10:00:00.000+0000")
+	writeIssueFixture(t, fixtures, "EV-3", "To Do", "2024-01-04T10:00:00.000+0000")
+
+	fake, err := NewFakeJira(os.DirFS(fixtures))
+	require.NoError(t, err)
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client, err := NewJiraClientFromConfig(&AppConfig{JIRAURL: server.URL, JIRAUsername: "ci@example.com", JIRAToken: "token"})
+	require.NoError(t, err)
+	return fixtures, fake, client
+}
+
+func TestUpdateJiraDetails(t *testing.T) {
+	t.Run("Only new and changed tickets are fetched", func(t *testing.T) {
+		fixtures, fake, client := newUpdateFixtures(t)
+		previous := client.FetchJiraDetails([]string{"EV-1", "EV-2"})
+		writeIssueFixture(t, fixtures, "EV-2", "Done", "2024-01-05T10:00:00.000+0000")
+
+		before := len(fake.Requests())
+		response := client.UpdateJiraDetails(previous, []string{"EV-3", "EV-1"}, false)
+
+		assert.Equal(t, &EvidenceUpdate{
+			Added:     []string{"EV-3"},
+			Removed:   []string{},
+			Changed:   []string{"EV-2"},
+			Unchanged: []string{"EV-1"},
+		}, response.Update)
+		assert.Equal(t, []string{
+			"GET /rest/api/2/issue/EV-1?fields=updated",
+			"GET /rest/api/2/issue/EV-2?fields=updated",
+			"GET /rest/api/2/issue/EV-2?expand=changelog",
+			"GET /rest/api/2/issue/EV-3?expand=changelog",
+		}, fake.Requests()[before:])
+
+		require.Len(t, response.Tasks, 3)
+		assert.Equal(t, previous.Tasks[0], response.Tasks[0])
+		assert.Equal(t, "Done", response.Tasks[1].Status)
+		assert.Equal(t, "EV-3", response.Tasks[2].Key)
+	})
+
+	t.Run("Prune removes tickets that are no longer referenced", func(t *testing.T) {
+		_, _, client := newUpdateFixtures(t)
+		previous := client.FetchJiraDetails([]string{"EV-1", "EV-2"})
+
+		response := client.UpdateJiraDetails(previous, []string{"ev-2", "EV-3"}, true)
+		assert.Equal(t, []string{"EV-1"}, response.Update.Removed)
+		assert.Equal(t, []string{"EV-2"}, response.Update.Unchanged)
+		assert.Equal(t, []string{"EV-3"}, response.Update.Added)
+		require.Len(t, response.Tasks, 2)
+		assert.Equal(t, "EV-2", response.Tasks[0].Key)
+	})
+
+	t.Run("Tickets that failed before are refetched", func(t *testing.T) {
+		_, _, client := newUpdateFixtures(t)
+		previous := TransitionCheckResponse{Tasks: []JiraTransitionResult{
+			{Key: "EV-1", Status: ErrorStatus, Error: &TaskError{Code: ErrorCodeNetworkError}},
+			{Key: "EV-404", Status: "Done", Updated: "2024-01-02T10:00:00.000+0000"},
+		}}
+
+		response := client.UpdateJiraDetails(previous, nil, false)
+		assert.Equal(t, []string{"EV-1", "EV-404"}, response.Update.Changed)
+		assert.Equal(t, "Done", response.Tasks[0].Status)
+		require.NotNil(t, response.Tasks[1].Error)
+		assert.Equal(t, ErrorCodeNotFound, response.Tasks[1].Error.Code)
+	})
+}
+
+func TestCheckUpdateAsOf(t *testing.T) {
+	asOf := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		previousAsOf  string
+		asOf          time.Time
+		expectedError bool
+	}{
+		{name: "Neither uses as-of"},
+		{name: "Only the update uses as-of", asOf: asOf},
+		{name: "Same instant", previousAsOf: asOf.Format(JiraTimeFormat), asOf: asOf},
+		{name: "Different instant", previousAsOf: asOf.Format(JiraTimeFormat), asOf: asOf.Add(time.Hour), expectedError: true},
+		{name: "Previous as-of dropped", previousAsOf: asOf.Format(JiraTimeFormat), expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkUpdateAsOf(TransitionCheckResponse{AsOf: tt.previousAsOf}, &AppConfig{AsOf: tt.asOf})
+			if !tt.expectedError {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *ValidationError
+			require.True(t, errors.As(err, &validationErr))
+			assert.Equal(t, "as-of", validationErr.Field)
+		})
+	}
+}
+
+func TestRunUpdateCommand(t *testing.T) {
+	_, _, client := newUpdateFixtures(t)
+	dir := t.TempDir()
+	previousFile := filepath.Join(dir, "previous.json")
+	outputFile := filepath.Join(dir, "updated.json")
+
+	config := &AppConfig{
+		JIRAURL:      client.baseURL,
+		JIRAUsername: "ci@example.com",
+		JIRAToken:    "token",
+		OutputFile:   previousFile,
+	}
+	require.NoError(t, saveJiraResults(client.FetchJiraDetails([]string{"EV-1"}), config))
+
+	config.OutputFile = outputFile
+	require.NoError(t, runUpdateCommand(&FlagConfig{InputFile: previousFile}, []string{"EV-1", "EV-2"}, config))
+
+	data, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	var response TransitionCheckResponse
+	require.NoError(t, json.Unmarshal(data, &response))
+	assert.Equal(t, CurrentSchemaVersion, response.SchemaVersion)
+	assert.Equal(t, []string{"EV-2"}, response.Update.Added)
+	assert.Equal(t, []string{"EV-1"}, response.Update.Unchanged)
+	require.Len(t, response.Tasks, 2)
+	assert.NotNil(t, response.Tasks[0].Metrics, "reused tickets are enriched again")
+
+	err = runUpdateCommand(&FlagConfig{InputFile: previousFile}, []string{"EV-1", "../EV-2"}, config)
+	assert.ErrorContains(t, err, "does not match the JIRA ID regex")
+
+	err = runUpdateCommand(&FlagConfig{InputFile: previousFile, Prune: true}, nil, config)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--prune needs the current JIRA IDs")
+
+	t.Run("Keys of the previous file are checked before any request", func(t *testing.T) {
+		_, fake, client := newUpdateFixtures(t)
+		tamperedFile := filepath.Join(dir, "tampered.json")
+		tampered := TransitionCheckResponse{Tasks: []JiraTransitionResult{
+			{Key: "EV-1", Status: "Done", Updated: "2024-01-02T10:00:00.000+0000", Transitions: []Transition{}},
+			{Key: "../../myself", Status: "Done", Updated: "2024-01-02T10:00:00.000+0000", Transitions: []Transition{}},
+		}}
+		require.NoError(t, saveJiraResults(tampered, &AppConfig{OutputFile: tamperedFile}))
+
+		config := &AppConfig{JIRAURL: client.baseURL, JIRAUsername: "ci@example.com", JIRAToken: "token", OutputFile: outputFile}
+		err := runUpdateCommand(&FlagConfig{InputFile: tamperedFile}, nil, config)
+		assert.ErrorContains(t, err, tamperedFile+": validation failed for JIRA ID='../../myself'")
+		assert.Empty(t, fake.Requests())
+	})
+}